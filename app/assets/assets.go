@@ -2,5 +2,11 @@ package assets
 
 import "embed"
 
-//go:embed templates/*
+// Templates holds the scaffold templates, the per-framework app overlays, the
+// per-stack generators for optional files such as the Makefile, and the
+// license texts named by SPDX identifier. The templates are embedded with
+// all: so that dotfiles like .gitkeep, which keep otherwise empty template
+// directories in the embed, and __init__.py files are included.
+//
+//go:embed all:templates frameworks/* generators/* licenses/*
 var Templates embed.FS
@@ -0,0 +1,22 @@
+"""Main application entry point."""
+
+from fastapi import FastAPI
+
+app = FastAPI()
+
+
+@app.get("/health")
+def health() -> dict[str, str]:
+    """Report service health."""
+    return {"status": "ok"}
+
+
+def main() -> None:
+    """Run the application with uvicorn."""
+    import uvicorn
+
+    uvicorn.run(app, host="0.0.0.0", port=8000)
+
+
+if __name__ == "__main__":
+    main()
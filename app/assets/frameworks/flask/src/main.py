@@ -0,0 +1,20 @@
+"""Main application entry point."""
+
+from flask import Flask
+
+app = Flask(__name__)
+
+
+@app.get("/health")
+def health() -> dict[str, str]:
+    """Report service health."""
+    return {"status": "ok"}
+
+
+def main() -> None:
+    """Run the development server."""
+    app.run(host="0.0.0.0", port=8000)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add app|infra",
+	Short: "Add the app or infra structure to an existing project",
+	Long: `Add the app or infra template subtree, with its Python package markers, to
+the project in the current directory. Existing files are kept unless --force
+is given, and a non-empty target directory is refused without it.
+Example: appinit add infra
+Example: appinit add app --force`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"app", "infra"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		resetRunState()
+		subtree := args[0]
+
+		if err := loadTemplateDir(); err != nil {
+			return err
+		}
+		if err := loadTemplateGroups(); err != nil {
+			return err
+		}
+		if err := loadTemplateRenames(); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(subtree)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(entries) > 0 && !force {
+			return fmt.Errorf("%s already exists and is not empty; use --force to add the templates into it anyway", subtree)
+		}
+
+		if err := createSubtree(".", subtree); err != nil {
+			return err
+		}
+		log().Info("subtree added successfully", "subtree", subtree, "created", len(result.Created), "overwritten", len(result.Overwritten), "skipped", len(result.Skipped))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	// Shares create's --force, so createFile applies the same overwrite policy
+	addCmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files and add into a non-empty directory")
+}
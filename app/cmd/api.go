@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"io/fs"
+	"log/slog"
+)
+
+// CreateOptions configures a scaffold run started from Go code.
+type CreateOptions struct {
+	// Name is the project directory to create, like --name.
+	Name string
+	// AppOnly and InfraOnly scaffold a single area, like --app-only and --infra-only.
+	AppOnly   bool
+	InfraOnly bool
+	// TargetDir is the directory the scaffold is written under; empty means the
+	// current directory.
+	TargetDir string
+	// Templates replaces the embedded templates. It is laid out like the
+	// embedded templates directory: root files plus one directory per subtree.
+	Templates fs.FS
+	// Logger receives the run's log records; nil means slog.Default().
+	Logger *slog.Logger
+}
+
+// apiTemplates holds CreateOptions.Templates for the running Create call.
+var apiTemplates fs.FS
+
+// Create scaffolds a project the same way the create command does, without
+// parsing arguments or exiting the process; the create command itself builds
+// CreateOptions from its flags and calls Create. Options CreateOptions does
+// not cover keep their defaults. Create uses package state and is not safe
+// for concurrent use.
+func Create(opts CreateOptions) (Result, error) {
+	appName, appOnly, infraOnly, outputDir = opts.Name, opts.AppOnly, opts.InfraOnly, opts.TargetDir
+	if name, err := normalizeAppName(appName); err == nil {
+		// validateCreateFlags reports names that cannot be normalized
+		appName = name
+	}
+	apiTemplates = opts.Templates
+	previous := logger
+	logger = opts.Logger
+	defer func() {
+		appName, appOnly, infraOnly, outputDir = "", false, false, ""
+		apiTemplates, logger = nil, previous
+	}()
+
+	result = Result{}
+	err := executeCreate()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	return result, err
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// archivePath is the .tar.gz file the scaffold is written to instead of the filesystem.
+var archivePath string
+
+// archiveFS is a writeFS that collects the scaffold in memory and writes it
+// out as a gzip-compressed tar. Entry names are relative to root, so the
+// archive unpacks to the layout create would have written under root.
+type archiveFS struct {
+	mu      sync.Mutex
+	root    string
+	names   []string
+	entries map[string]*archiveEntry
+}
+
+// archiveEntry is one directory, file, or symlink of an archiveFS.
+type archiveEntry struct {
+	dir  bool
+	mode fs.FileMode
+	data []byte
+	// link is the target of a symlink entry.
+	link string
+}
+
+// newArchiveFS returns an empty archiveFS for paths under root.
+func newArchiveFS(root string) *archiveFS {
+	return &archiveFS{root: root, entries: map[string]*archiveEntry{}}
+}
+
+// entryName returns the archive name of an OS path, or "." for the root.
+func (a *archiveFS) entryName(name string) (string, error) {
+	rel, err := filepath.Rel(a.root, name)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", &fs.PathError{Op: "archive", Path: name, Err: errors.New("path is outside the archive root")}
+	}
+	return rel, nil
+}
+
+func (a *archiveFS) Stat(name string) (fs.FileInfo, error) {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if rel == "." {
+		return archiveInfo{name: ".", entry: &archiveEntry{dir: true, mode: 0755}}, nil
+	}
+	entry, ok := a.entries[rel]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return archiveInfo{name: path.Base(rel), entry: entry}, nil
+}
+
+// Lstat is Stat: symlinks are stored as entries and never followed.
+func (a *archiveFS) Lstat(name string) (fs.FileInfo, error) {
+	return a.Stat(name)
+}
+
+func (a *archiveFS) Readlink(name string) (string, error) {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry, ok := a.entries[rel]
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrNotExist}
+	}
+	if entry.link == "" {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: errors.New("not a symlink")}
+	}
+	return entry.link, nil
+}
+
+func (a *archiveFS) Symlink(oldname, newname string) error {
+	rel, err := a.entryName(newname)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.entries[rel]; ok {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrExist}
+	}
+	a.add(rel, &archiveEntry{mode: fs.ModeSymlink | 0777, link: oldname})
+	return nil
+}
+
+func (a *archiveFS) Remove(name string) error {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.entries[rel]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(a.entries, rel)
+	a.names = slices.DeleteFunc(a.names, func(n string) bool { return n == rel })
+	return nil
+}
+
+// RemoveAll removes name and every entry below it; like os.RemoveAll, a
+// missing name is not an error.
+func (a *archiveFS) RemoveAll(name string) error {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	below := func(n string) bool { return rel == "." || n == rel || strings.HasPrefix(n, rel+"/") }
+	for n := range a.entries {
+		if below(n) {
+			delete(a.entries, n)
+		}
+	}
+	a.names = slices.DeleteFunc(a.names, below)
+	return nil
+}
+
+func (a *archiveFS) ReadFile(name string) ([]byte, error) {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry, ok := a.entries[rel]
+	if !ok || entry.dir {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte{}, entry.data...), nil
+}
+
+// ReadDir returns the entries directly below the directory name, sorted by name.
+func (a *archiveFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if entry, ok := a.entries[rel]; rel != "." && (!ok || !entry.dir) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	var entries []fs.DirEntry
+	for _, n := range a.names {
+		if path.Dir(n) == rel {
+			entries = append(entries, fs.FileInfoToDirEntry(archiveInfo{name: path.Base(n), entry: a.entries[n]}))
+		}
+	}
+	slices.SortFunc(entries, func(x, y fs.DirEntry) int { return strings.Compare(x.Name(), y.Name()) })
+	return entries, nil
+}
+
+func (a *archiveFS) Mkdir(name string, perm fs.FileMode) error {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.entries[rel]; ok || rel == "." {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	a.add(rel, &archiveEntry{dir: true, mode: perm})
+	return nil
+}
+
+func (a *archiveFS) MkdirAll(name string, perm fs.FileMode) error {
+	rel, err := a.entryName(name)
+	if err != nil || rel == "." {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	dir := ""
+	for _, part := range strings.Split(rel, "/") {
+		dir = path.Join(dir, part)
+		if _, ok := a.entries[dir]; !ok {
+			a.add(dir, &archiveEntry{dir: true, mode: perm})
+		}
+	}
+	return nil
+}
+
+func (a *archiveFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if entry, ok := a.entries[rel]; ok {
+		// Like os.WriteFile, replacing a file keeps its mode
+		entry.data = append([]byte{}, data...)
+		return nil
+	}
+	a.add(rel, &archiveEntry{mode: perm, data: append([]byte{}, data...)})
+	return nil
+}
+
+func (a *archiveFS) Chmod(name string, mode fs.FileMode) error {
+	rel, err := a.entryName(name)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry, ok := a.entries[rel]
+	if !ok {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+	}
+	entry.mode = mode
+	return nil
+}
+
+// add records a new entry; the caller holds a.mu.
+func (a *archiveFS) add(rel string, entry *archiveEntry) {
+	a.names = append(a.names, rel)
+	a.entries[rel] = entry
+}
+
+// writeArchive writes every entry, in creation order, to a .tar.gz at dest.
+func (a *archiveFS) writeArchive(dest string) error {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	modTime := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, name := range a.names {
+		entry := a.entries[name]
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    int64(entry.mode.Perm()),
+			ModTime: modTime,
+		}
+		if entry.mode&fs.ModeSetgid != 0 {
+			hdr.Mode |= 02000
+		}
+		if entry.dir {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+		} else if entry.link != "" {
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = entry.link
+		} else {
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = int64(len(entry.data))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return errors.Join(tw.Close(), gz.Close(), f.Close())
+}
+
+// archiveInfo is the fs.FileInfo of an archiveFS entry.
+type archiveInfo struct {
+	name  string
+	entry *archiveEntry
+}
+
+func (i archiveInfo) Name() string       { return i.name }
+func (i archiveInfo) Size() int64        { return int64(len(i.entry.data)) }
+func (i archiveInfo) ModTime() time.Time { return time.Time{} }
+func (i archiveInfo) IsDir() bool        { return i.entry.dir }
+func (i archiveInfo) Sys() any           { return nil }
+
+func (i archiveInfo) Mode() fs.FileMode {
+	if i.entry.dir {
+		return i.entry.mode | fs.ModeDir
+	}
+	return i.entry.mode
+}
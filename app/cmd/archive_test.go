@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readArchive returns the entries of a .tar.gz file by name.
+func readArchive(t *testing.T, name string) map[string]*tar.Header {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	entries := map[string]*tar.Header{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[hdr.Name] = hdr
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir := chdirTemp(t)
+	mustExecute(t, "create", "--name", "p", "--archive", "out.tar.gz", "--no-tree")
+	assertMissing(t, dir, "p")
+
+	entries := readArchive(t, "out.tar.gz")
+	for name, typ := range map[string]byte{
+		"p/":                                    tar.TypeDir,
+		"p/README.md":                           tar.TypeReg,
+		"p/app/":                                tar.TypeDir,
+		"p/app/src/main.py":                     tar.TypeReg,
+		"p/app/tests/__init__.py":               tar.TypeReg,
+		"p/app/scripts/upgrade_dependencies.py": tar.TypeReg,
+		"p/infra/app.py":                        tar.TypeReg,
+	} {
+		hdr, ok := entries[name]
+		if !ok {
+			t.Errorf("archive has no %s", name)
+			continue
+		}
+		if hdr.Typeflag != typ {
+			t.Errorf("%s: type %q, want %q", name, hdr.Typeflag, typ)
+		}
+	}
+	if hdr, ok := entries["p/app/scripts/upgrade_dependencies.py"]; ok && hdr.Mode&0111 == 0 {
+		t.Errorf("scripts are not executable in the archive: mode %o", hdr.Mode)
+	}
+}
+
+func TestArchiveMustBeTarGz(t *testing.T) {
+	dir := chdirTemp(t)
+	if _, err := execute(t, "create", "--name", "p", "--archive", "out.zip"); err == nil {
+		t.Error("--archive out.zip: want an error")
+	}
+	assertMissing(t, dir, "p", "out.zip")
+}
+
+func TestArchiveFS(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "out")
+	a := newArchiveFS(root)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(a.MkdirAll(filepath.Join(root, "p", "app", "src"), 0755))
+	must(a.WriteFile(filepath.Join(root, "p", "README.md"), []byte("readme"), 0644))
+	must(a.WriteFile(filepath.Join(root, "p", "app", "main.py"), nil, 0644))
+
+	entries, err := a.ReadDir(filepath.Join(root, "p"))
+	must(err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if want := []string{"README.md", "app"}; !slices.Equal(names, want) {
+		t.Errorf("ReadDir(p) = %q, want %q", names, want)
+	}
+	if entries, err := a.ReadDir(filepath.Join(root, "p", "app", "src")); err != nil || len(entries) != 0 {
+		t.Errorf("ReadDir(p/app/src) = %v, %v; want an empty directory", entries, err)
+	}
+	if _, err := a.ReadDir(filepath.Join(root, "p", "README.md")); err == nil {
+		t.Error("ReadDir of a file: want an error")
+	}
+	if err := a.WriteFile(filepath.Join(filepath.Dir(root), "outside"), nil, 0644); err == nil {
+		t.Error("WriteFile outside the root: want an error")
+	}
+
+	must(a.RemoveAll(filepath.Join(root, "p", "app")))
+	must(a.RemoveAll(filepath.Join(root, "missing")))
+	if want := []string{"p", "p/README.md"}; !slices.Equal(a.names, want) {
+		t.Errorf("entries after RemoveAll = %q, want %q", a.names, want)
+	}
+	if got, err := a.ReadFile(filepath.Join(root, "p", "README.md")); err != nil || string(got) != "readme" {
+		t.Errorf("ReadFile(p/README.md) = %q, %v", got, err)
+	}
+}
+
+func TestArchiveWithPostProcessing(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":       "readme",
+		"tpl/app/src/main.py": "print()",
+	})
+	if err := os.MkdirAll(filepath.Join(dir, "tpl", "app", "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mustExecute(t, "create", "--name", "p", "--template-dir", "tpl", "--archive", "out.tgz",
+		"--gitkeep", "--checksums", "p/SHA256SUMS", "--stats", "--no-tree")
+	assertMissing(t, dir, "p")
+
+	entries := readArchive(t, "out.tgz")
+	for _, name := range []string{"p/app/data/.gitkeep", "p/SHA256SUMS"} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("archive has no %s", name)
+		}
+	}
+	if hdr, ok := entries["p/app/src/.gitkeep"]; ok {
+		t.Errorf("archive has %s in a directory with files", hdr.Name)
+	}
+}
+
+func TestArchiveSummaryBytes(t *testing.T) {
+	chdirTemp(t)
+	mustExecute(t, "create", "--name", "p", "--archive", "out.tgz", "--summary-json", "summary.json", "--no-tree")
+	summary := readSummary(t, "summary.json")
+	var want int64
+	for _, hdr := range readArchive(t, "out.tgz") {
+		want += hdr.Size
+	}
+	if summary.Bytes == 0 || summary.Bytes != want {
+		t.Errorf("summary bytes = %d, want the %d bytes in the archive", summary.Bytes, want)
+	}
+}
+
+func TestArchiveValidateOutput(t *testing.T) {
+	if _, err := pythonInterpreter(); err != nil {
+		t.Skip(err)
+	}
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/app/src/main.py":  "print()\n",
+		"tpl/app/package.json": "{}\n",
+		"tpl/app/config.toml":  "[tool]\nname = \"x\"\n",
+	})
+	mustExecute(t, "create", "--name", "p", "--template-dir", "tpl", "--archive", "ok.tgz", "--validate-output", "--no-tree")
+	if _, ok := readArchive(t, "ok.tgz")["p/app/src/main.py"]; !ok {
+		t.Error("archive has no p/app/src/main.py")
+	}
+
+	writeTree(t, dir, map[string]string{"tpl/app/src/main.py": "def (:\n"})
+	_, err := execute(t, "create", "--name", "p", "--template-dir", "tpl", "--archive", "bad.tgz", "--validate-output", "--no-tree")
+	if err == nil || !strings.Contains(err.Error(), "main.py is invalid") {
+		t.Fatalf("err = %v, want one naming main.py", err)
+	}
+	assertMissing(t, dir, "p", "bad.tgz")
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// compareFormat selects the compare-projects output format.
+var compareFormat string
+
+// compareIgnoreFile lists patterns, one per line, of paths compare-projects skips.
+const compareIgnoreFile = ".appinitignore"
+
+// maxDiffCells bounds the line-diff table so huge files are reported as differing without a diff.
+const maxDiffCells = 4_000_000
+
+// compareCmd represents the compare-projects command
+var compareCmd = &cobra.Command{
+	Use:   "compare-projects <dirA> <dirB>",
+	Short: "Show differences between two scaffolded projects",
+	Long: `Compare two existing project directories and report files and directories
+that exist in only one of them, plus files whose contents differ.
+Paths matching a pattern in either project's .appinitignore are skipped:
+a pattern without a slash matches a name at any depth, one with a slash
+matches the path from the project root, and a trailing slash matches only
+directories.
+Example: appinit compare-projects my-app my-app-fastapi
+Example: appinit compare-projects a b --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if compareFormat != "text" && compareFormat != "json" {
+			return fmt.Errorf("unsupported --format %q (supported: text, json)", compareFormat)
+		}
+		comparison, err := compareProjects(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if compareFormat == "json" {
+			return printJSON(comparison)
+		}
+		writeComparisonText(comparison)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().StringVar(&compareFormat, "format", "text", "Output format (text, json)")
+}
+
+// comparison holds the differences between two project trees.
+type comparison struct {
+	OnlyInA   []string     `json:"only_in_a"`
+	OnlyInB   []string     `json:"only_in_b"`
+	Differing []fileChange `json:"differing"`
+}
+
+// fileChange describes a path present in both trees with different contents.
+type fileChange struct {
+	Path   string   `json:"path"`
+	Binary bool     `json:"binary"`
+	Diff   []string `json:"diff,omitempty"`
+}
+
+// compareProjects walks both directories and collects their differences.
+func compareProjects(dirA, dirB string) (comparison, error) {
+	c := comparison{OnlyInA: []string{}, OnlyInB: []string{}, Differing: []fileChange{}}
+
+	ignores, err := loadIgnorePatterns(dirA, dirB)
+	if err != nil {
+		return c, err
+	}
+	entriesA, err := projectEntries(dirA, ignores)
+	if err != nil {
+		return c, err
+	}
+	entriesB, err := projectEntries(dirB, ignores)
+	if err != nil {
+		return c, err
+	}
+
+	for _, rel := range sortedKeys(entriesA) {
+		isDirA := entriesA[rel]
+		isDirB, ok := entriesB[rel]
+		if !ok {
+			c.OnlyInA = append(c.OnlyInA, rel)
+			continue
+		}
+		if isDirA != isDirB {
+			c.Differing = append(c.Differing, fileChange{Path: rel})
+			continue
+		}
+		if isDirA {
+			continue
+		}
+
+		change, same, err := compareFiles(filepath.Join(dirA, rel), filepath.Join(dirB, rel), rel)
+		if err != nil {
+			return c, err
+		}
+		if !same {
+			c.Differing = append(c.Differing, change)
+		}
+	}
+	for _, rel := range sortedKeys(entriesB) {
+		if _, ok := entriesA[rel]; !ok {
+			c.OnlyInB = append(c.OnlyInB, rel)
+		}
+	}
+	return c, nil
+}
+
+// ignorePattern is one line of an .appinitignore file.
+type ignorePattern struct {
+	pattern  string
+	anchored bool
+	dirOnly  bool
+}
+
+// loadIgnorePatterns reads the .appinitignore of each directory, skipping
+// blank lines and # comments. A directory without one contributes nothing.
+func loadIgnorePatterns(dirs ...string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	for _, dir := range dirs {
+		name := filepath.Join(dir, compareIgnoreFile)
+		content, err := os.ReadFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			p := ignorePattern{pattern: line}
+			if trimmed, ok := strings.CutSuffix(p.pattern, "/"); ok {
+				p.pattern, p.dirOnly = trimmed, true
+			}
+			if trimmed, ok := strings.CutPrefix(p.pattern, "/"); ok {
+				p.pattern, p.anchored = trimmed, true
+			}
+			p.anchored = p.anchored || strings.Contains(p.pattern, "/")
+			if _, err := path.Match(p.pattern, ""); err != nil || p.pattern == "" {
+				return nil, fmt.Errorf("%s:%d: invalid pattern %q", name, i+1, line)
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns, nil
+}
+
+// ignored reports whether the slash-separated relative path rel matches one of patterns.
+func ignored(patterns []ignorePattern, rel string, isDir bool) bool {
+	for _, p := range patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if !p.anchored {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(p.pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// projectEntries maps every path under root (relative, slash-separated) to
+// whether it is a directory, leaving out paths matching ignores.
+func projectEntries(root string, ignores []ignorePattern) (map[string]bool, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	entries := map[string]bool{}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored(ignores, rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		entries[rel] = d.IsDir()
+		return nil
+	})
+	return entries, err
+}
+
+// compareFiles reports whether two files match and, if not, how they differ.
+func compareFiles(pathA, pathB, rel string) (fileChange, bool, error) {
+	a, err := os.ReadFile(pathA)
+	if err != nil {
+		return fileChange{}, false, err
+	}
+	b, err := os.ReadFile(pathB)
+	if err != nil {
+		return fileChange{}, false, err
+	}
+	if bytes.Equal(a, b) {
+		return fileChange{}, true, nil
+	}
+
+	change := fileChange{Path: rel}
+	if isBinary(a) || isBinary(b) {
+		change.Binary = true
+		return change, false, nil
+	}
+	change.Diff = diffLines(strings.Split(string(a), "\n"), strings.Split(string(b), "\n"))
+	return change, false, nil
+}
+
+// isBinary reports whether content looks like binary data rather than text.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
+
+// diffLines returns the removed ("-") and added ("+") lines between a and b
+// using a longest-common-subsequence table.
+func diffLines(a, b []string) []string {
+	if len(a)*len(b) > maxDiffCells {
+		return nil
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "-"+a[i])
+			i++
+		default:
+			diff = append(diff, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+"+b[j])
+	}
+	return diff
+}
+
+// writeComparisonText prints the comparison for humans.
+func writeComparisonText(c comparison) {
+	for _, p := range c.OnlyInA {
+		fmt.Printf("only in A: %s\n", p)
+	}
+	for _, p := range c.OnlyInB {
+		fmt.Printf("only in B: %s\n", p)
+	}
+	for _, change := range c.Differing {
+		switch {
+		case change.Binary:
+			fmt.Printf("differs: %s (binary)\n", change.Path)
+		default:
+			fmt.Printf("differs: %s\n", change.Path)
+			for _, line := range change.Diff {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+	}
+	if len(c.OnlyInA)+len(c.OnlyInB)+len(c.Differing) == 0 {
+		fmt.Println("projects are identical")
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
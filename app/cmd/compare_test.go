@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareProjectsIgnore(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"a/.appinitignore":    "# build output\n*.log\nbuild/\n/app/local.txt\n",
+		"a/README.md":         "same\n",
+		"a/debug.log":         "a\n",
+		"a/build/out.txt":     "a\n",
+		"a/app/local.txt":     "a\n",
+		"a/app/main.py":       "print('a')\n",
+		"b/.appinitignore":    "# build output\n*.log\nbuild/\n/app/local.txt\n",
+		"b/README.md":         "same\n",
+		"b/app/sub/debug.log": "b\n",
+		"b/app/build":         "a file, not the ignored directory\n",
+		"b/app/main.py":       "print('b')\n",
+		"b/sub/app/local.txt": "not anchored to the root\n",
+	})
+	c, err := compareProjects("a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.OnlyInA) != 0 {
+		t.Errorf("only in A = %v, want none", c.OnlyInA)
+	}
+	if want := []string{"app/build", "app/sub", "sub", "sub/app", "sub/app/local.txt"}; !slices.Equal(c.OnlyInB, want) {
+		t.Errorf("only in B = %v, want %v", c.OnlyInB, want)
+	}
+	if len(c.Differing) != 1 || c.Differing[0].Path != "app/main.py" {
+		t.Errorf("differing = %v, want app/main.py", c.Differing)
+	}
+
+	writeTree(t, dir, map[string]string{"a/.appinitignore": "[\n"})
+	if _, err := compareProjects("a", "b"); err == nil {
+		t.Error("an invalid pattern was accepted")
+	}
+}
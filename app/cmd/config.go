@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// configNames are the config file names searched for, in order of precedence.
+var configNames = []string{".appinit.yaml", ".appinitrc"}
+
+// configPath and configFlags record the config file in use and the flags it set.
+var (
+	configPath  string
+	configFlags []string
+)
+
+// config holds create defaults read from a config file, bound to the create flags.
+var config = viper.New()
+
+func init() {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := configureLogging(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if cmd != createCmd {
+			return nil
+		}
+		return applyConfig(cmd.Flags())
+	}
+}
+
+// configFile returns the first config file found in the current directory,
+// then the home directory.
+func configFile() (string, bool) {
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	for _, dir := range dirs {
+		for _, name := range configNames {
+			p := filepath.Join(dir, name)
+			if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// applyConfig sets every flag not given on the command line from the config
+// file. Command-line flags always win over config values.
+func applyConfig(flags *pflag.FlagSet) error {
+	path, ok := configFile()
+	if !ok {
+		return nil
+	}
+	config.SetConfigFile(path)
+	config.SetConfigType("yaml")
+	if err := config.ReadInConfig(); err != nil {
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+	for _, key := range config.AllKeys() {
+		if !slices.Contains(configKeys(flags), key) {
+			warn("unknown key in config file, ignoring", "key", key, "path", path)
+		}
+	}
+	if err := config.BindPFlags(flags); err != nil {
+		return err
+	}
+	log().Debug("using config file", "path", path)
+	configPath, configFlags = path, nil
+
+	var problems []error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !config.InConfig(f.Name) {
+			return
+		}
+		if f.Changed {
+			log().Info("command-line flag overrides config file", "flag", f.Name, "path", path)
+			return
+		}
+		var err error
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			err = slice.Replace(config.GetStringSlice(f.Name))
+			f.Changed = true
+		} else {
+			err = flags.Set(f.Name, config.GetString(f.Name))
+		}
+		configFlags = append(configFlags, f.Name)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("config %s: invalid %s: %w", path, f.Name, err))
+		}
+	})
+	if len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// configKeys returns the config keys flags accepts, as viper lowercases them.
+func configKeys(flags *pflag.FlagSet) []string {
+	var keys []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden && f.Name != "help" {
+			keys = append(keys, f.Name)
+		}
+	})
+	return keys
+}
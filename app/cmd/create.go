@@ -2,16 +2,232 @@ package cmd
 
 import (
 	"appinit/assets"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// createFlagOptions receives the create flags that CreateOptions covers; the
+// create command passes them on to Create.
+var createFlagOptions CreateOptions
+
 // appName is the name of the root directory to create.
 var appName string
+
+// outputDir is the directory the scaffold is written under; empty means the current directory.
+var outputDir string
+
+// templateDir is a directory of templates used instead of the embedded ones.
+var templateDir string
+
+// templateFS is the template tree scaffolded from, laid out like the embedded
+// templates directory: root files plus one directory per subtree.
+var templateFS fs.FS
+
+// nameMaxLength is the longest --name accepted.
+var nameMaxLength int
 var appOnly bool
 var infraOnly bool
+var rootOnly bool
+
+// skipExistingDir skips template directories whose destination already matches exactly.
+var skipExistingDir bool
+
+// abortOnDirtyGit refuses to scaffold into a git work tree with uncommitted changes.
+var abortOnDirtyGit bool
+
+// logSample emits only every Nth per-file debug log; 0 or 1 logs every file.
+var logSample int
+
+// fileLogCount counts per-file log events for --log-sample.
+var fileLogCount int
+
+// gitkeep drops a .gitkeep into created directories that would otherwise be empty.
+var gitkeep bool
+
+// stripGitkeep creates template directories kept by a .gitkeep or .keep
+// without copying the placeholder file itself.
+var stripGitkeep bool
+
+// keepFiles are the placeholder names that keep an empty template directory
+// in git and the embed.
+var keepFiles = []string{".gitkeep", ".keep"}
+
+// checksumsFile receives a sha256sum-compatible listing of every generated file.
+var checksumsFile string
+
+// overwriteReadme allows replacing an existing README.md, which is otherwise always preserved.
+var overwriteReadme bool
+
+// noTree skips the tree of created paths printed after a successful run.
+var noTree bool
+
+// stats prints a per-extension breakdown of the generated files.
+var stats bool
+
+// verifyAfterWrite reads every written file back and checks it against the intended content.
+var verifyAfterWrite bool
+
+// verifiedCount counts files checked by --verify-after-write.
+var verifiedCount int
+
+// continueOnError attempts every remaining path after a failure and reports
+// all failures at the end.
+var continueOnError bool
+
+// structureOnly creates the directory layout without writing any template file.
+var structureOnly bool
+
+// structureMarkers keeps the empty __init__.py package markers under --structure-only.
+var structureMarkers bool
+
+// followSymlinks copies the targets of template symlinks instead of recreating the links.
+var followSymlinks bool
+
+// workers bounds how many template files are written concurrently.
+var workers int
+
+// timeBudget is a soft deadline after which optional steps are skipped.
+var timeBudget time.Duration
+
+// runStart records when the scaffold began, for --time-budget.
+var runStart time.Time
+
+// budgetSkipped records the optional steps already reported as skipped.
+var budgetSkipped = map[string]bool{}
+
+// overrideFlags holds the raw --override dest=path values.
+var overrideFlags []string
+
+// overrides maps target-relative destination paths to the content replacing the template file.
+var overrides map[string][]byte
+
+// usedOverrides records which overrides matched a scaffolded file.
+var usedOverrides = map[string]bool{}
+
+// groupWritable adds the group-write bit to created files and directories.
+var groupWritable bool
+
+// setgid sets the setgid bit on created directories so new files inherit their group.
+var setgid bool
+
+// mergeJSON deep-merges template .json files into existing destination files.
+var mergeJSON bool
+
+// mergeJSONArrays selects how arrays are combined under --merge-json.
+var mergeJSONArrays string
+
+// runTests runs the scaffolded projects' tests after creation.
+var runTests bool
+
+// validatePythonIdentifiers checks that Python package directory names are importable.
+var validatePythonIdentifiers bool
+
+// pythonPackageDirs lists the package directories, relative to the scaffold
+// target, that runCreate creates with an empty __init__.py.
+var pythonPackageDirs = []string{"app/tests", "infra/stacks", "infra/tests"}
+
+// gitBranch scaffolds onto this branch of the target's git repository.
+var gitBranch string
+
+// gitCommit commits the scaffold on --git-branch before switching back.
+var gitCommit bool
+
+// summaryJSON receives a JSON summary of the run; "-" means stdout.
+var summaryJSON string
+
+// normalizeDirNames transforms generated directory names (lower, kebab, snake); empty leaves them as authored.
+var normalizeDirNames string
+
+// onlySubtrees restricts the default scaffold to these template subtrees.
+var onlySubtrees []string
+
+// scanSecrets checks file content for credentials before writing and warns on matches.
+var scanSecrets bool
+
+// scanSecretsFail makes a secret match abort the scaffold; it implies scanSecrets.
+var scanSecretsFail bool
+
+// mirrorTests creates a tests package for each source package instead of a flat tests directory.
+var mirrorTests bool
+
+// keepOnError leaves a partially created project in place when scaffolding fails.
+var keepOnError bool
+
+// gitInit runs git init in the new project and commits the scaffold.
+var gitInit bool
+
+// stacks lists the supported --stack values.
+var stacks = []string{"python", "go", "node"}
+
+// stack is the language stack being scaffolded; it decides which marker files are added.
+var stack string
+
+// stackExplicit records that --stack was given rather than defaulted.
+var stackExplicit bool
+
+// deps are extra dependencies added to the app's pyproject.toml.
+var deps []string
+
+// jsonOutput prints a JSON report of the created paths to stdout instead of the info logs.
+var jsonOutput bool
+
+// force overwrites files that already exist with different content.
+var force bool
+
+// skipExisting keeps files that already exist with different content.
+// Without it or force, such a file fails the run.
+var skipExisting bool
+
+// existingSkipped counts existing files kept under --skip-existing.
+var existingSkipped int
+
+// dryRun logs the directories and files a run would create without writing anything.
+var dryRun bool
+
+// plannedDirs and plannedFiles count what a --dry-run would create.
+var plannedDirs, plannedFiles int
+
+// plannedSizes maps each file a --dry-run would write to its size in bytes.
+var plannedSizes = map[string]int64{}
+
+// appFramework selects a framework-specific overlay for the app templates.
+var appFramework string
+
+// appFrameworks lists the supported --app-framework values. "none" keeps the bare layout.
+var appFrameworks = []string{"none", "fastapi", "flask"}
+
+// locale selects localized template variants named like README.ja.md.
+var locale string
+
+// locales lists the supported --locale values.
+var locales = []string{"en", "ja"}
+
+// defaultLocale is used when a template has no variant for the chosen locale.
+const defaultLocale = "en"
+
+// windowsMaxPath is the classic Windows MAX_PATH limit, including the terminating NUL.
+const windowsMaxPath = 260
+
+// windowsPathMargin is how close to MAX_PATH a path may get before a warning.
+const windowsPathMargin = 20
 
 // createCmd represents the create command
 var createCmd = &cobra.Command{
@@ -20,183 +236,1518 @@ var createCmd = &cobra.Command{
 	Long: `Create a new project structure. 
 Example: appinit create --name my-app          (creates my-app with app and infra)
 Example: appinit create --app-only             (creates app directory only)
-Example: appinit create --infra-only           (creates infra directory only)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if appOnly && infraOnly {
-			slog.Error("cannot use both --app-only and --infra-only")
-			os.Exit(1)
-		}
-		if appName == "" && !appOnly && !infraOnly {
-			slog.Error("either --name, --app-only, or --infra-only is required")
-			os.Exit(1)
-		}
-		if err := runCreate(); err != nil {
-			slog.Error("create command failed", "error", err)
-			os.Exit(1)
-		}
-	},
+Example: appinit create --infra-only           (creates infra directory only)
+Example: appinit create --root-only            (creates root-level files in the current directory)`,
 }
 
-func init() {
-	rootCmd.AddCommand(createCmd)
-	createCmd.Flags().StringVar(&appName, "name", "", "Name of the root directory to create")
-	createCmd.Flags().BoolVar(&appOnly, "app-only", false, "Create only the app directory")
-	createCmd.Flags().BoolVar(&infraOnly, "infra-only", false, "Create only the infra directory")
+// runCreateCmd is the create command: it prompts if asked to, then builds
+// CreateOptions from the flags and calls Create.
+func runCreateCmd(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	prompt, err := wantInteractive(cmd.Flags())
+	if err != nil {
+		return err
+	}
+	if prompt {
+		if err := promptCreateFlags(cmd.Flags(), os.Stdin, os.Stderr); err != nil {
+			return err
+		}
+	}
+	stackExplicit = cmd.Flags().Changed("stack")
+	defer func() { stackExplicit = false }()
+	opts := createFlagOptions
+	opts.Logger = logger
+	_, err = Create(opts)
+	return err
 }
 
-// runCreate scaffolds the project structure based on flags.
-func runCreate() error {
-	if appOnly {
-		if err := createDirectory("app"); err != nil {
+// executeCreate runs a create with the current options: it resolves and
+// validates them, runs the preflight checks, scaffolds, and writes the
+// requested reports.
+func executeCreate() error {
+	resetRunState()
+	if jsonOutput {
+		// The JSON report replaces the info logs
+		previous := logger
+		logger = slog.New(levelFilter{log().Handler(), slog.LevelWarn})
+		defer func() { logger = previous }()
+	}
+	// Flag validation reads the template tree, so resolve it first
+	cleanup, err := useTemplateFlags()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	if err := loadTemplateGroups(); err != nil {
+		return err
+	}
+	if err := validateCreateFlags(createCmd.Flags()); err != nil {
+		return err
+	}
+	if err := loadTemplateRenames(); err != nil {
+		return err
+	}
+	if err := checkGroupModeSupport(); err != nil {
+		return err
+	}
+	if dryRun {
+		noteDryRunSkips(createCmd.Flags())
+	}
+	if runTests && !dryRun {
+		// Fail before scaffolding rather than after
+		if _, _, err := testCommand(); err != nil {
 			return err
 		}
-		if err := walkTemplates("templates/app", "app"); err != nil {
+	}
+	if validateOutput && !dryRun {
+		if _, err := pythonInterpreter(); err != nil {
 			return err
 		}
-		if err := createDirectory("app/tests"); err != nil {
+	}
+	if validatePythonIdentifiers {
+		if err := checkPythonPackages(); err != nil {
 			return err
 		}
-		if err := createFile("app/tests/__init__.py", []byte{}); err != nil {
-			return err
+	}
+	if err := loadOverrides(); err != nil {
+		return err
+	}
+	var archive *archiveFS
+	if archivePath != "" {
+		if _, err := os.Stat(archivePath); err == nil && !force {
+			return fmt.Errorf("archive %s already exists; use --force to overwrite it", archivePath)
 		}
-		slog.Info("app directory created successfully")
-	} else if infraOnly {
-		if err := createDirectory("infra"); err != nil {
+		archive = newArchiveFS(outputRoot())
+		destFS = archive
+		defer func() { destFS = osFS{} }()
+	}
+	if !dryRun && archive == nil {
+		// The probe file would be the only write of a dry run
+		if err := checkWritable(); err != nil {
 			return err
 		}
-		if err := walkTemplates("templates/infra", "infra"); err != nil {
+	}
+	if runtime.GOOS == "windows" {
+		if err := checkPathLength(); err != nil {
 			return err
 		}
-		if err := createDirectory("infra/stacks"); err != nil {
+	}
+	if abortOnDirtyGit && !force {
+		if err := checkCleanGit(gitCheckDir()); err != nil {
 			return err
 		}
-		if err := createFile("infra/stacks/__init__.py", []byte{}); err != nil {
+	}
+	if !dryRun {
+		if err := destFS.MkdirAll(outputRoot(), 0755); err != nil {
+			return fmt.Errorf("create --output-dir: %w", err)
+		}
+	}
+
+	if gitBranch != "" && !dryRun {
+		err = runCreateOnBranch()
+	} else {
+		err = runCreate()
+	}
+	if err != nil && continueOnError && len(result.Failed) > 0 {
+		log().Error("paths failed", "count", len(result.Failed))
+		err = fmt.Errorf("%d paths failed: %w", len(result.Failed), err)
+	}
+	if err == nil && archive != nil {
+		if err = archive.writeArchive(archivePath); err == nil {
+			log().Info("archive written", "path", archivePath, "entries", len(archive.names))
+		}
+	}
+	if err == nil && gitInit && !dryRun && withinBudget("git") {
+		initGitRepo(targetDir())
+	}
+	if err == nil && preCommit && !dryRun && archive == nil {
+		// After git init, so the hooks go into the project's own repository
+		err = installPreCommit()
+	}
+	if summaryJSON != "" {
+		if summaryErr := writeSummaryJSON(summaryJSON, err); summaryErr != nil {
+			return errors.Join(err, summaryErr)
+		}
+	}
+	if jsonOutput {
+		if reportErr := printCreateReport(err); reportErr != nil {
+			return errors.Join(err, reportErr)
+		}
+	}
+	if err == nil && printsTree() {
+		printCreatedTree()
+	}
+	return err
+}
+
+func init() {
+	templateFS = embeddedTemplates()
+
+	// Assigned here because Create validates against createCmd's flags
+	createCmd.RunE = runCreateCmd
+
+	rootCmd.AddCommand(createCmd)
+	createCmd.Flags().StringVar(&createFlagOptions.Name, "name", "", "Name of the root directory to create")
+	createCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Prompt for the project name, areas, and license (the default when run from a terminal without flags)")
+	createCmd.Flags().StringVarP(&createFlagOptions.TargetDir, "output-dir", "o", ".", "Parent directory the project is created under (created if missing)")
+	createCmd.Flags().StringVar(&templateDir, "template-dir", "", "Scaffold from this template directory instead of the built-in templates")
+	createCmd.Flags().StringVar(&templateSource, "template", "", "Scaffold from a local template directory or a git repository such as github.com/org/templates (cached in ~/.appinit/templates)")
+	createCmd.Flags().StringVar(&templateRepo, "template-repo", "", "Scaffold from the templates/ directory of this git repository (shallow-cloned for the run)")
+	createCmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch or tag of --template-repo to use")
+	createCmd.Flags().IntVar(&nameMaxLength, "name-max-length", defaultNameMaxLength, "Longest --name accepted")
+	createCmd.Flags().BoolVar(&createFlagOptions.AppOnly, "app-only", false, "Create only the app directory")
+	createCmd.Flags().BoolVar(&createFlagOptions.InfraOnly, "infra-only", false, "Create only the infra directory")
+	createCmd.Flags().BoolVar(&rootOnly, "root-only", false, "Create only the root-level files (into --name if given, otherwise the current directory)")
+	createCmd.Flags().BoolVar(&skipExistingDir, "skip-existing-dir", false, "Skip descending into existing directories that already match the templates")
+	createCmd.Flags().BoolVar(&abortOnDirtyGit, "abort-on-dirty-git", false, "Refuse to scaffold into a git repository with uncommitted changes, unless --force")
+	createCmd.Flags().IntVar(&logSample, "log-sample", 0, "Emit only every Nth per-file debug log (errors and the summary are always logged)")
+	createCmd.Flags().BoolVar(&gitkeep, "gitkeep", false, "Add a .gitkeep to created directories that end up empty")
+	createCmd.Flags().BoolVar(&stripGitkeep, "strip-gitkeep", false, "Create template directories kept by a .gitkeep or .keep without copying the placeholder")
+	createCmd.Flags().StringVar(&checksumsFile, "checksums", "", "Write a SHA256SUMS-style file listing every generated file")
+	createCmd.Flags().BoolVar(&overwriteReadme, "overwrite-readme", false, "Allow replacing an existing README.md")
+	createCmd.Flags().BoolVar(&noTree, "no-tree", false, "Do not print the tree of created paths after a successful run")
+	createCmd.Flags().BoolVar(&stats, "stats", false, "Report generated file counts and sizes by extension")
+	createCmd.Flags().BoolVar(&verifyAfterWrite, "verify-after-write", false, "Read each written file back and fail if its content does not match")
+	createCmd.Flags().BoolVar(&structureOnly, "structure-only", false, "Create only the directory layout, without writing any template file")
+	createCmd.Flags().BoolVar(&structureMarkers, "structure-markers", false, "Keep the empty __init__.py package markers under --structure-only")
+	createCmd.Flags().BoolVar(&followSymlinks, "follow-symlinks", false, "Copy what symlinks in --template-dir point to instead of recreating the links")
+	createCmd.Flags().IntVar(&workers, "workers", runtime.NumCPU(), "Number of template files to write concurrently")
+	createCmd.Flags().DurationVar(&timeBudget, "time-budget", 0, "Soft time limit after which optional steps (--verify-after-write, --validate-output, --checksums, --run-tests, --git) are skipped")
+	createCmd.Flags().StringArrayVar(&overrideFlags, "override", nil, "Replace the template file at a destination with a local file (dest=path, repeatable)")
+	createCmd.Flags().BoolVar(&groupWritable, "group-writable", false, "Make created files and directories group-writable (Unix only)")
+	createCmd.Flags().BoolVar(&setgid, "setgid", false, "Set the setgid bit on created directories (Unix only)")
+	createCmd.Flags().BoolVar(&mergeJSON, "merge-json", false, "Deep-merge template .json files into existing ones instead of replacing them")
+	createCmd.Flags().StringVar(&mergeJSONArrays, "merge-json-arrays", "keep", "How --merge-json combines arrays ("+strings.Join(mergeArrayStrategies, ", ")+")")
+	createCmd.Flags().BoolVar(&runTests, "run-tests", false, "Run the stack's tests (pytest for python, go test for go) in the scaffolded projects and fail if tests fail")
+	createCmd.Flags().BoolVar(&validatePythonIdentifiers, "validate-python-identifiers", false, "Fail before writing if a Python package directory name is not a valid identifier")
+	createCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Keep scaffolding past failed files and directories, report every failure at the end, and keep the partial project")
+	createCmd.Flags().BoolVar(&keepOnError, "keep-on-error", false, "Keep the partially created project directory when scaffolding fails")
+	createCmd.Flags().BoolVar(&gitInit, "git", false, "Run git init in the new project directory and commit the scaffold")
+	createCmd.Flags().StringVar(&gitBranch, "git-branch", "", "Scaffold onto this branch (created if missing) of the target's git repository, then switch back; the work tree must be clean unless --force")
+	createCmd.Flags().BoolVar(&gitCommit, "git-commit", false, "Commit the scaffold on --git-branch")
+	createCmd.Flags().StringVar(&license, "license", "", "Write a LICENSE file with this SPDX license, e.g. MIT or Apache-2.0")
+	createCmd.Flags().StringVar(&licenseAuthor, "author", "", "Copyright holder named in the --license text")
+	createCmd.Flags().StringVar(&archivePath, "archive", "", "Write the project to this .tar.gz file instead of the filesystem")
+	createCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print a JSON report of created and skipped paths to stdout; only warnings and errors are logged")
+	createCmd.Flags().StringVar(&summaryJSON, "summary-json", "", "Write a JSON summary of the run to this file (- for stdout)")
+	createCmd.Flags().StringVar(&normalizeDirNames, "normalize-dir-names", "", "Transform generated directory names ("+strings.Join(dirNameStyles, ", ")+")")
+	createCmd.Flags().StringSliceVar(&withGroups, "with", nil, "Include these optional template groups, e.g. docs (repeatable or comma-separated)")
+	createCmd.Flags().StringSliceVar(&onlySubtrees, "only", nil, "Scaffold only these template subtrees, e.g. app (repeatable or comma-separated)")
+	createCmd.Flags().BoolVar(&scanSecrets, "scan-secrets", false, "Warn when file content looks like it contains credentials")
+	createCmd.Flags().BoolVar(&scanSecretsFail, "scan-secrets-fail", false, "Abort instead of warning when --scan-secrets finds credentials")
+	createCmd.Flags().BoolVar(&mirrorTests, "mirror-tests", false, "Mirror the source package layout under tests/")
+	createCmd.Flags().StringArrayVar(&deps, "dep", nil, "Add a dependency to the app's pyproject.toml, e.g. httpx>=0.28 (repeatable)")
+	createCmd.Flags().BoolVar(&makefile, "makefile", false, "Generate a Makefile with install, test, lint, and run targets for the project's stack")
+	createCmd.Flags().BoolVar(&preCommit, "pre-commit", false, "Generate a .pre-commit-config.yaml for the project's stack and install the hooks if pre-commit and git are available")
+	createCmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files that differ from the templates instead of failing")
+	createCmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "Keep existing files that differ from the templates instead of failing")
+	createCmd.Flags().BoolVar(&validateOutput, "validate-output", false, "Syntax-check generated JSON, Go, Python, TOML, and YAML files and roll back on the first invalid one")
+	createCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Log the directories and files that would be created without writing anything")
+	createCmd.Flags().StringVar(&stack, "stack", "python", "Language stack of the templates ("+strings.Join(stacks, ", ")+"); the built-in templates are python")
+	createCmd.Flags().StringVar(&locale, "locale", defaultLocale, "Locale for localized templates ("+strings.Join(locales, ", ")+")")
+	createCmd.Flags().StringVar(&appFramework, "app-framework", "none", "Web framework for the app templates ("+strings.Join(appFrameworks, ", ")+")")
+	setEnum(createCmd.Flags(), "stack", stacks)
+	setEnum(createCmd.Flags(), "locale", locales)
+	setEnum(createCmd.Flags(), "app-framework", appFrameworks)
+	setEnum(createCmd.Flags(), "license", licenses())
+	setEnum(createCmd.Flags(), "merge-json-arrays", mergeArrayStrategies)
+	setEnum(createCmd.Flags(), "normalize-dir-names", dirNameStyles)
+	setHostDefault(createCmd.Flags(), "workers", "the number of CPUs")
+	registerFlagCompletions(createCmd, "name", "dep", "author")
+}
+
+// embeddedTemplates returns the templates directory of the embed.
+func embeddedTemplates() fs.FS {
+	// The embedded tree always has a templates directory
+	sub, _ := fs.Sub(assets.Templates, "templates")
+	return sub
+}
+
+// loadTemplateDir points templateFS at the --template-repo clone or at
+// --template-dir, failing if it is not a directory, or otherwise at the
+// Create templates or the embedded ones.
+func loadTemplateDir() error {
+	if templateClone != "" {
+		templateFS = os.DirFS(filepath.Join(templateClone, "templates"))
+		return nil
+	}
+	if templateDir == "" {
+		templateFS = embeddedTemplates()
+		if apiTemplates != nil {
+			templateFS = apiTemplates
+		}
+		return nil
+	}
+	info, err := os.Stat(templateDir)
+	if err != nil {
+		return fmt.Errorf("invalid --template-dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid --template-dir: %s is not a directory", templateDir)
+	}
+	templateFS = os.DirFS(templateDir)
+	log().Debug("using template directory", "path", templateDir)
+	return nil
+}
+
+// resetRunState clears the per-run counters and records, so repeated Create
+// calls in one process start from scratch.
+func resetRunState() {
+	runStart = time.Now()
+	fileLogCount, verifiedCount = 0, 0
+	plannedDirs, plannedFiles, existingSkipped = 0, 0, 0
+	plannedSizes = map[string]int64{}
+	budgetSkipped = map[string]bool{}
+	usedOverrides = map[string]bool{}
+	templateRenames = nil
+}
+
+// countTrue returns how many of the given flags are set.
+func countTrue(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+// outputRoot returns the directory the scaffold is written under.
+func outputRoot() string {
+	if outputDir == "" {
+		return "."
+	}
+	return outputDir
+}
+
+// targetDir returns the directory the selected mode scaffolds into.
+func targetDir() string {
+	if appOnly || infraOnly || appName == "" {
+		return outputRoot()
+	}
+	return filepath.Join(outputRoot(), appName)
+}
+
+// checkPathLength fails when the longest destination path would exceed the
+// Windows MAX_PATH limit, and warns when it comes close.
+func checkPathLength() error {
+	longest, err := longestDestPath()
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(longest) >= windowsMaxPath:
+		return fmt.Errorf("destination path %s is %d characters, over the Windows limit of %d; enable long path support or scaffold into a shorter directory",
+			longest, len(longest), windowsMaxPath-1)
+	case len(longest) >= windowsMaxPath-windowsPathMargin:
+		warn("destination paths are close to the Windows path length limit", "path", longest, "length", len(longest), "limit", windowsMaxPath-1)
+	}
+	return nil
+}
+
+// longestDestPath returns the longest absolute destination path the selected mode would write.
+func longestDestPath() (string, error) {
+	src, dest := ".", targetDir()
+	switch {
+	case appOnly:
+		src, dest = "app", filepath.Join(outputRoot(), "app")
+	case infraOnly:
+		src, dest = "infra", filepath.Join(outputRoot(), "infra")
+	}
+
+	root, err := filepath.Abs(dest)
+	if err != nil {
+		return "", err
+	}
+	longest := root
+	err = fs.WalkDir(templateFS, src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
 			return err
 		}
-		if err := createDirectory("infra/tests"); err != nil {
+		if rootOnly && d.IsDir() && p != src {
+			// Only root-level files are written
+			return fs.SkipDir
+		}
+		rel := p
+		if src != "." {
+			rel = strings.TrimPrefix(p, src)
+		}
+		candidate := filepath.Join(root, filepath.FromSlash(rel))
+		if len(candidate) > len(longest) {
+			longest = candidate
+		}
+		return nil
+	})
+	return longest, err
+}
+
+// gitCheckDir returns the directory whose git status guards the scaffold: the
+// target itself if it exists, otherwise the directory it will be created in.
+func gitCheckDir() string {
+	target := targetDir()
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return target
+	}
+	return filepath.Dir(target)
+}
+
+// runCreate scaffolds the project structure based on flags.
+func runCreate() error {
+	if appOnly {
+		if err := createSubtree(outputRoot(), "app"); err != nil {
 			return err
 		}
-		if err := createFile("infra/tests/__init__.py", []byte{}); err != nil {
+		log().Info("app directory created successfully")
+	} else if infraOnly {
+		if err := createSubtree(outputRoot(), "infra"); err != nil {
 			return err
 		}
-		slog.Info("infra directory created successfully")
-	} else {
-		// Default: create root directory with both app and infra
-		if err := createDirectory(appName); err != nil {
+		log().Info("infra directory created successfully")
+	} else if rootOnly {
+		// Retrofit root-level files into --name, or the current directory
+		target := targetDir()
+		if appName != "" {
+			if err := createDirectory(target); err != nil {
+				return err
+			}
+		}
+		if err := copyRootTemplates(templateFS, target); err != nil {
 			return err
 		}
-
-		// Copy root-level files
-		if err := copyRootTemplates(appName); err != nil {
+		if err := writeLicense(target); err != nil {
 			return err
 		}
-
-		// Copy app and infra
-		if err := createTemplates(appName); err != nil {
+		log().Info("root files created successfully", "path", target)
+	} else {
+		// Default: create root directory with both app and infra
+		root := targetDir()
+		_, statErr := destFS.Stat(root)
+		if err := createProject(root); err != nil {
+			if os.IsNotExist(statErr) && !dryRun && !continueOnError {
+				removePartialProject(root)
+			}
 			return err
 		}
+		log().Info("project structure created successfully", "name", appName)
+	}
 
-		// Create missing directories and __init__.py files
-		if err := createDirectory(appName + "/app/tests"); err != nil {
+	if makefile {
+		if err := writeGenerated("makefile", "Makefile", "Makefile"); err != nil {
 			return err
 		}
-		if err := createFile(appName+"/app/tests/__init__.py", []byte{}); err != nil {
+	}
+	if preCommit {
+		if err := writeGenerated("pre-commit", "pre-commit-config.yaml", ".pre-commit-config.yaml"); err != nil {
 			return err
 		}
-		if err := createDirectory(appName + "/infra/stacks"); err != nil {
-			return err
+	}
+	if existingSkipped > 0 {
+		log().Info("existing files kept", "count", existingSkipped)
+	}
+	if dryRun {
+		log().Info("dry run complete, nothing written", "directories", plannedDirs, "files", plannedFiles)
+		if stats {
+			printStats()
 		}
-		if err := createFile(appName+"/infra/stacks/__init__.py", []byte{}); err != nil {
+		return nil
+	}
+	log().Info("files written", "created", len(result.Created)-len(result.Directories), "updated", len(result.Overwritten), "unchanged", len(result.Unchanged))
+	if gitkeep {
+		if err := addGitkeeps(); err != nil {
 			return err
 		}
-		if err := createDirectory(appName + "/infra/tests"); err != nil {
+	}
+	if validateOutput && withinBudget("validate-output") {
+		if err := validateGeneratedFiles(); err != nil {
 			return err
 		}
-		if err := createFile(appName+"/infra/tests/__init__.py", []byte{}); err != nil {
+	}
+	if checksumsFile != "" && withinBudget("checksums") {
+		if err := writeChecksums(checksumsFile); err != nil {
 			return err
 		}
-
-		slog.Info("project structure created successfully", "name", appName)
+	}
+	if stats {
+		printStats()
+	}
+	for _, dest := range sortedKeys(overrides) {
+		if !usedOverrides[dest] {
+			warn("override did not match any scaffolded file", "dest", dest)
+		}
+	}
+	if verifyAfterWrite {
+		log().Info("files verified after write", "count", verifiedCount)
+	}
+	if runTests && withinBudget("run-tests") {
+		return runProjectTests()
 	}
 	return nil
 }
 
-// createDirectory creates a directory, ignoring errors if it already exists.
-func createDirectory(name string) error {
-	if err := os.Mkdir(name, 0755); err != nil && !os.IsExist(err) {
-		slog.Error("failed to create directory", "path", name, "error", err)
+// projectMarkerFile is written to the root of every project so destroy can
+// tell an appinit project from any directory with a similar layout.
+const projectMarkerFile = ".appinit.json"
+
+// projectMarker is the content of projectMarkerFile. It holds nothing that
+// changes between runs, so a rerun leaves the marker unchanged.
+type projectMarker struct {
+	Generator string `json:"generator"`
+	Name      string `json:"name"`
+}
+
+// writeProjectMarker writes projectMarkerFile into root, except under
+// --structure-only, which writes no files.
+func writeProjectMarker(root string) error {
+	if structureOnly {
+		return nil
+	}
+	body, err := json.MarshalIndent(projectMarker{Generator: "appinit", Name: projectName()}, "", "  ")
+	if err != nil {
 		return err
 	}
-	slog.Debug("directory created", "path", name)
-	return nil
+	return createFile(filepath.Join(root, projectMarkerFile), append(body, '\n'), 0644)
 }
 
-// createFile creates a file, ignoring errors if it already exists.
-func createFile(path string, content []byte) error {
-	if err := os.WriteFile(path, content, 0644); err != nil && !os.IsExist(err) {
-		slog.Error("failed to create file", "path", path, "error", err)
+// createProject scaffolds the default layout into root: the root directory
+// with the root files and every selected subtree.
+func createProject(root string) error {
+	if err := createDirectory(root); err != nil {
 		return err
 	}
-	slog.Debug("file created", "path", path)
-	return nil
+
+	// Copy root-level files
+	var errs []error
+	if !keepGoing(&errs, copyRootTemplates(templateFS, root)) {
+		return errors.Join(errs...)
+	}
+	if !keepGoing(&errs, writeLicense(root)) {
+		return errors.Join(errs...)
+	}
+	if !keepGoing(&errs, writeProjectMarker(root)) {
+		return errors.Join(errs...)
+	}
+
+	// Copy the template subtrees (app, infra, ...)
+	subtrees, err := selectedSubtrees()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if !keepGoing(&errs, createTemplates(root, subtrees)) {
+		return errors.Join(errs...)
+	}
+
+	// Create missing directories and __init__.py files
+	if !keepGoing(&errs, createPythonMarkers(root, subtrees...)) {
+		return errors.Join(errs...)
+	}
+	if mirrorTests {
+		keepGoing(&errs, mirrorTestPackages(root, subtrees...))
+	}
+	return errors.Join(errs...)
 }
 
-// createTemplates copies all template files from embedded assets to the base directory.
-func createTemplates(baseDir string) error {
-	return walkTemplates("templates", baseDir)
+// keepGoing appends err, if any, to errs and reports whether scaffolding
+// should go on: always after success, and after a failure only under
+// --continue-on-error.
+func keepGoing(errs *[]error, err error) bool {
+	if err == nil {
+		return true
+	}
+	*errs = append(*errs, err)
+	return continueOnError
 }
 
-// copyRootTemplates copies root-level files (.gitignore, README, workspace config).
-func copyRootTemplates(baseDir string) error {
-	rootFiles := []string{".gitignore", "README.md", "repo.code-workspace"}
+// createSubtree scaffolds a single template subtree, such as app, with its
+// Python markers directly under base.
+func createSubtree(base, subtree string) error {
+	dir := filepath.Join(base, subtree)
+	if err := createDirectory(dir); err != nil {
+		return err
+	}
+	var errs []error
+	if !keepGoing(&errs, walkSubtree(subtree, dir)) {
+		return errors.Join(errs...)
+	}
+	if !keepGoing(&errs, createPythonMarkers(base, subtree)) {
+		return errors.Join(errs...)
+	}
+	if mirrorTests {
+		keepGoing(&errs, mirrorTestPackages(base, subtree))
+	}
+	return errors.Join(errs...)
+}
 
-	for _, filename := range rootFiles {
-		srcPath := "templates/" + filename
-		destPath := baseDir + "/" + filename
+// removePartialProject deletes a project root this run created and failed to
+// finish, unless --keep-on-error asks to leave it for inspection.
+func removePartialProject(root string) {
+	if keepOnError {
+		log().Info("keeping partially created project", "path", root)
+		return
+	}
+	if err := destFS.RemoveAll(root); err != nil {
+		log().Error("failed to remove partially created project", "path", root, "error", err)
+		return
+	}
+	log().Info("removed partially created project", "path", root)
+}
 
-		content, err := assets.Templates.ReadFile(srcPath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				// Skip if file doesn't exist
-				continue
-			}
-			return err
+// addGitkeeps writes a .gitkeep into every directory created by this run that
+// is still empty, so the structure survives a git commit.
+func addGitkeeps() error {
+	for _, p := range slices.Clone(result.Created) {
+		entries, err := destFS.ReadDir(p)
+		if err != nil || len(entries) > 0 {
+			// Not a directory, or already has content
+			continue
 		}
-
-		if err := createFile(destPath, content); err != nil {
+		if err := createFile(filepath.Join(p, ".gitkeep"), []byte{}, 0644); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// walkTemplates recursively copies template directory structure to destination.
-func walkTemplates(srcDir, destDir string) error {
-	entries, err := assets.Templates.ReadDir(srcDir)
-	if err != nil {
-		return err
+// generatedFiles returns every file this run wrote, without duplicates or directories.
+func generatedFiles() []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, p := range slices.Concat(result.Created, result.Overwritten) {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		if info, err := destFS.Stat(p); err == nil && info.Mode().IsRegular() {
+			files = append(files, p)
+		}
 	}
+	return files
+}
 
-	for _, entry := range entries {
-		srcPath := srcDir + "/" + entry.Name()
-		destPath := destDir + "/" + entry.Name()
-
-		if entry.IsDir() {
-			if err := createDirectory(destPath); err != nil {
-				return err
-			}
-			if err := walkTemplates(srcPath, destPath); err != nil {
-				return err
-			}
-		} else {
-			content, err := assets.Templates.ReadFile(srcPath)
-			if err != nil {
-				return err
-			}
-			if err := createFile(destPath, content); err != nil {
-				return err
-			}
+// writeChecksums writes the SHA-256 hash and target-relative path of every file
+// this run generated, in the format read by sha256sum -c.
+func writeChecksums(dest string) error {
+	root := targetDir()
+	var lines []string
+	for _, p := range generatedFiles() {
+		content, err := destFS.ReadFile(p)
+		if err != nil {
+			return err
 		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		lines = append(lines, hex.EncodeToString(sum[:])+"  "+filepath.ToSlash(rel)+"\n")
 	}
-	return nil
+	slices.SortFunc(lines, func(a, b string) int { return strings.Compare(a[66:], b[66:]) })
+
+	if err := destFS.WriteFile(dest, []byte(strings.Join(lines, "")), 0644); err != nil {
+		return err
+	}
+	log().Info("checksums written", "path", dest, "files", len(lines))
+	return nil
+}
+
+// printStats prints the number and total size of generated files per
+// extension, or under --dry-run of the files the run would write.
+func printStats() {
+	type extStats struct {
+		files int
+		bytes int64
+	}
+	sizes := plannedSizes
+	if !dryRun {
+		sizes = map[string]int64{}
+		for _, p := range generatedFiles() {
+			if info, err := destFS.Stat(p); err == nil {
+				sizes[p] = info.Size()
+			}
+		}
+	}
+	byExt := map[string]*extStats{}
+	for p, size := range sizes {
+		ext := filepath.Ext(p)
+		if ext == "" {
+			ext = "(none)"
+		}
+		if byExt[ext] == nil {
+			byExt[ext] = &extStats{}
+		}
+		byExt[ext].files++
+		byExt[ext].bytes += size
+	}
+
+	for _, ext := range sortedKeys(byExt) {
+		fmt.Printf("%s: %d files, %s\n", ext, byExt[ext].files, formatBytes(byExt[ext].bytes))
+	}
+}
+
+// printsTree reports whether the run ends with the tree of created paths:
+// not under --quiet or --no-tree, or when stdout carries JSON.
+func printsTree() bool {
+	return !quiet && !noTree && !jsonOutput && summaryJSON != "-"
+}
+
+// printCreatedTree prints the paths this run created or overwrote, or under
+// --dry-run would write, as an indented tree rooted at the target directory.
+// A dry run also shows the size of each file.
+func printCreatedTree() {
+	root := targetDir()
+	dirs := map[string]bool{}
+	for _, p := range result.Directories {
+		dirs[p] = true
+	}
+	entries, sizes := map[string]bool{}, map[string]int64{}
+	for _, p := range slices.Concat(result.Created, result.Overwritten) {
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		entries[rel] = dirs[p]
+		if size, ok := plannedSizes[p]; ok {
+			sizes[rel] = size
+		}
+		// Files written into existing directories still need their parents shown
+		for parent := path.Dir(rel); parent != "."; parent = path.Dir(parent) {
+			entries[parent] = true
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Println(filepath.ToSlash(root) + "/")
+	printTree("", entries, sizes)
+}
+
+// formatBytes renders a byte count with a binary unit suffix.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
+
+// createPythonMarkers creates the Python package directories of the given
+// subtrees under base, each with an empty __init__.py unless the templates
+// provide one. Other stacks get no marker files.
+func createPythonMarkers(base string, subtrees ...string) error {
+	if stack != "python" {
+		return nil
+	}
+	for _, pkg := range pythonPackageDirs {
+		subtree, _, _ := strings.Cut(pkg, "/")
+		if !slices.Contains(subtrees, subtree) {
+			continue
+		}
+		dir := filepath.Join(base, filepath.FromSlash(pkg))
+		if err := createDirectory(dir); err != nil {
+			return err
+		}
+		if templateWrites(path.Join(pkg, "__init__.py")) {
+			// The template walk already wrote it
+			continue
+		}
+		if marker := filepath.Join(dir, "__init__.py"); !structureSkips(marker) {
+			if err := createFile(marker, []byte{}, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// templateWrites reports whether the template walk writes the file at src, a
+// path in templateFS.
+func templateWrites(src string) bool {
+	_, err := fs.Stat(templateFS, src)
+	return err == nil && !excludedByGroup(src)
+}
+
+// structureSkips reports whether --structure-only leaves out the file at
+// path: every file except, under --structure-markers, __init__.py markers.
+func structureSkips(path string) bool {
+	return structureOnly && !(structureMarkers && filepath.Base(path) == "__init__.py")
+}
+
+// createDirectory creates a directory, ignoring errors if it already exists.
+func createDirectory(name string) error {
+	if dryRun {
+		if _, err := destFS.Stat(name); err == nil || createdThisRun(name) {
+			return nil
+		}
+		log().Info("would create directory", "path", name)
+		plannedDirs++
+		recordCreated(name, true)
+		return nil
+	}
+	err := destFS.Mkdir(name, 0755)
+	if err != nil && !os.IsExist(err) {
+		log().Error("failed to create directory", "path", name, "error", err)
+		recordFailure(name)
+		return err
+	}
+	if err == nil {
+		if err := applyGroupMode(name, 0755, true); err != nil {
+			return err
+		}
+		recordCreated(name, true)
+	}
+	logFileEvent("directory created", "path", name)
+	return nil
+}
+
+// createFile writes a file with mode perm. A file that existed before this
+// run is left alone when its content already matches, overwritten under
+// --force, merged into under --merge-json, and otherwise handled by
+// keepExisting.
+func createFile(path string, content []byte, perm os.FileMode) (err error) {
+	defer func() {
+		if err != nil {
+			recordFailure(path)
+		}
+	}()
+	if override, ok := overrideFor(path); ok {
+		log().Debug("using override for template file", "path", path)
+		content = override
+	}
+	if isScript(path, content) {
+		// Scripts must keep LF endings or they fail to execute on Linux
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	}
+	_, statErr := destFS.Stat(path)
+	// Files written again in one run are
+	// recorded once, as created
+	again := createdThisRun(path)
+	existing := statErr == nil && !again
+	var current []byte
+	if existing {
+		var err error
+		if current, err = destFS.ReadFile(path); err != nil {
+			return err
+		}
+	}
+	merge := existing && mergeJSON && filepath.Ext(path) == ".json"
+	if merge {
+		merged, err := mergeJSONContent(current, content, mergeJSONArrays)
+		if err != nil {
+			return fmt.Errorf("merge %s: %w", path, err)
+		}
+		log().Debug("merged template into existing JSON file", "path", path)
+		content = merged
+	}
+	if existing && bytes.Equal(current, content) {
+		log().Debug("unchanged", "path", path)
+		recordPath(&result.Unchanged, path)
+		return nil
+	}
+	if existing && preserveReadme(path) {
+		log().Info("existing README.md preserved; use --overwrite-readme to replace it", "path", path)
+		recordPath(&result.Skipped, path)
+		return nil
+	}
+	if existing && !merge && !force && filepath.Base(path) != "README.md" {
+		// README.md reaches here only with --overwrite-readme
+		return keepExisting(path)
+	}
+	if scanSecrets || scanSecretsFail {
+		if err := scanForSecrets(path, content); err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		log().Info("would create file", "path", path, "bytes", len(content))
+		stateMu.Lock()
+		plannedSizes[path] = int64(len(content))
+		if !again {
+			plannedFiles++
+		}
+		stateMu.Unlock()
+		if !again {
+			recordFile(path, statErr == nil)
+		}
+		return nil
+	}
+	if err := destFS.WriteFile(path, content, perm); err != nil {
+		log().Error("failed to create file", "path", path, "error", err)
+		return err
+	}
+	if statErr == nil {
+		// WriteFile keeps the mode of a file it overwrites
+		if err := destFS.Chmod(path, perm); err != nil {
+			return err
+		}
+	}
+	if err := applyGroupMode(path, perm, false); err != nil {
+		return err
+	}
+	if verifyAfterWrite && withinBudget("verify-after-write") {
+		if err := verifyFile(path, content); err != nil {
+			log().Error("file verification failed", "path", path, "error", err)
+			return err
+		}
+	}
+	if !again {
+		recordFile(path, statErr == nil)
+	}
+	logFileEvent("file created", "path", path)
+	return nil
+}
+
+// keepExisting resolves a conflict with a file that existed before this run
+// and differs from the template, without --force: the file is kept under
+// --skip-existing, and the run fails otherwise. A dry run only warns, so it
+// reports every conflict.
+func keepExisting(path string) error {
+	switch {
+	case skipExisting:
+		log().Info("file already exists, keeping it", "path", path)
+	case dryRun:
+		warn("file already exists with different content; the run would fail without --force or --skip-existing", "path", path)
+	default:
+		return &fs.PathError{Op: "create", Path: path, Err: fmt.Errorf("%w with different content; use --force to overwrite it or --skip-existing to keep it", fs.ErrExist)}
+	}
+	recordPath(&result.Skipped, path)
+	stateMu.Lock()
+	existingSkipped++
+	stateMu.Unlock()
+	return nil
+}
+
+// recordFile records a written file as overwritten if it existed before, or
+// as created.
+func recordFile(path string, overwritten bool) {
+	if overwritten {
+		recordPath(&result.Overwritten, path)
+	} else {
+		recordCreated(path, false)
+	}
+}
+
+// createSymlink recreates a template symlink at path, pointing at target.
+// Existing paths follow the same rules as createFile.
+func createSymlink(path, target string) (err error) {
+	defer func() {
+		if err != nil {
+			recordFailure(path)
+		}
+	}()
+	info, statErr := destFS.Lstat(path)
+	existing := statErr == nil && !createdThisRun(path)
+	if existing {
+		if current, err := destFS.Readlink(path); err == nil && current == target {
+			log().Debug("unchanged", "path", path)
+			recordPath(&result.Unchanged, path)
+			return nil
+		}
+		if !force {
+			return keepExisting(path)
+		}
+	}
+	if dryRun {
+		log().Info("would create symlink", "path", path, "target", target)
+		stateMu.Lock()
+		plannedFiles++
+		stateMu.Unlock()
+		recordCreated(path, false)
+		return nil
+	}
+	if statErr == nil {
+		if info.IsDir() {
+			return fmt.Errorf("cannot replace directory %s with a symlink", path)
+		}
+		if err := destFS.Remove(path); err != nil {
+			return err
+		}
+	}
+	if err := destFS.Symlink(target, path); err != nil {
+		log().Error("failed to create symlink", "path", path, "error", err)
+		return err
+	}
+	if statErr == nil {
+		recordPath(&result.Overwritten, path)
+	} else {
+		recordCreated(path, false)
+	}
+	logFileEvent("symlink created", "path", path, "target", target)
+	return nil
+}
+
+// loadOverrides parses --override dest=path values and reads each local file.
+func loadOverrides() error {
+	overrides = map[string][]byte{}
+	for _, value := range overrideFlags {
+		dest, src, ok := strings.Cut(value, "=")
+		if !ok || dest == "" || src == "" {
+			return fmt.Errorf("invalid --override %q: expected dest=path", value)
+		}
+		content, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("invalid --override %q: %w", value, err)
+		}
+		overrides[path.Clean(filepath.ToSlash(dest))] = content
+	}
+	return nil
+}
+
+// overrideFor returns the --override content for a destination path, if any.
+func overrideFor(dest string) ([]byte, bool) {
+	if len(overrides) == 0 {
+		return nil, false
+	}
+	rel, err := filepath.Rel(targetDir(), dest)
+	if err != nil {
+		return nil, false
+	}
+	rel = filepath.ToSlash(rel)
+	content, ok := overrides[rel]
+	if ok {
+		stateMu.Lock()
+		usedOverrides[rel] = true
+		stateMu.Unlock()
+	}
+	return content, ok
+}
+
+// logFileEvent logs a per-file debug message, keeping only every Nth one under --log-sample.
+func logFileEvent(msg string, args ...any) {
+	stateMu.Lock()
+	fileLogCount++
+	count := fileLogCount
+	stateMu.Unlock()
+	if logSample > 1 {
+		if count%logSample != 0 {
+			return
+		}
+		args = append(args, "count", count)
+	}
+	log().Debug(msg, args...)
+}
+
+// noteDryRunSkips logs each set step that needs the files on disk, which a
+// dry run skips.
+func noteDryRunSkips(flags *pflag.FlagSet) {
+	for _, name := range []string{"git-branch", "run-tests", "checksums", "validate-output"} {
+		if flags.Changed(name) {
+			log().Info("dry run, skipping step that needs the files on disk", "step", name)
+		}
+	}
+}
+
+// withinBudget reports whether an optional step may still run under --time-budget,
+// logging the first time a step is skipped.
+func withinBudget(step string) bool {
+	if timeBudget <= 0 || time.Since(runStart) < timeBudget {
+		return true
+	}
+	stateMu.Lock()
+	first := !budgetSkipped[step]
+	budgetSkipped[step] = true
+	stateMu.Unlock()
+	if first {
+		warn("time budget exceeded, skipping optional step", "step", step, "budget", timeBudget)
+	}
+	return false
+}
+
+// verifyFile reads path back and compares its hash with the content that was written.
+func verifyFile(path string, want []byte) error {
+	got, err := destFS.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(got) != sha256.Sum256(want) {
+		return fmt.Errorf("content written to %s does not match: wrote %d bytes, read back %d", path, len(want), len(got))
+	}
+	stateMu.Lock()
+	verifiedCount++
+	stateMu.Unlock()
+	return nil
+}
+
+// preserveReadme reports whether an existing file at path is a hand-written
+// README.md that must not be replaced. READMEs written earlier in this run are fair game.
+func preserveReadme(path string) bool {
+	return filepath.Base(path) == "README.md" && !overwriteReadme && !createdThisRun(path)
+}
+
+// templateMode returns the mode a template file is written with. embed.FS
+// carries no permission bits, so files under a bin/ or scripts/ directory are
+// made executable by convention; package markers there are not.
+func templateMode(srcPath string) os.FileMode {
+	if path.Base(srcPath) == "__init__.py" {
+		return 0644
+	}
+	for _, dir := range strings.Split(path.Dir(srcPath), "/") {
+		if dir == "bin" || dir == "scripts" {
+			return 0755
+		}
+	}
+	return 0644
+}
+
+// isScript reports whether a file is a shell script or starts with a shebang.
+func isScript(path string, content []byte) bool {
+	return filepath.Ext(path) == ".sh" || bytes.HasPrefix(content, []byte("#!"))
+}
+
+// templateSubtrees discovers the top-level template directories, such as app and
+// infra, so a new subtree added to the embed is scaffolded without code changes.
+func templateSubtrees() ([]string, error) {
+	entries, err := fs.ReadDir(templateFS, ".")
+	if err != nil {
+		return nil, err
+	}
+	var subtrees []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			subtrees = append(subtrees, entry.Name())
+		}
+	}
+	return subtrees, nil
+}
+
+// selectedSubtrees returns the subtrees chosen with --only, or every discovered subtree.
+func selectedSubtrees() ([]string, error) {
+	subtrees, err := templateSubtrees()
+	if err != nil || len(onlySubtrees) == 0 {
+		return subtrees, err
+	}
+	for _, name := range onlySubtrees {
+		if !slices.Contains(subtrees, name) {
+			return nil, fmt.Errorf("unknown --only subtree %q (available: %s)", name, strings.Join(subtrees, ", "))
+		}
+	}
+	return onlySubtrees, nil
+}
+
+// createTemplates copies the given template subtrees to the base directory.
+// Root-level files are handled separately by copyRootTemplates.
+func createTemplates(baseDir string, subtrees []string) error {
+	var errs []error
+	for _, subtree := range subtrees {
+		destDir := filepath.Join(baseDir, subtree)
+		if !keepGoing(&errs, createDirectory(destDir)) {
+			break
+		}
+		if !keepGoing(&errs, walkSubtree(subtree, destDir)) {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// walkSubtree copies a template subtree to destDir. For app, the files of the
+// selected --app-framework are planned over the subtree's, so each
+// destination is written once, with the framework's version.
+func walkSubtree(subtree, destDir string) error {
+	dirs, files, err := planTemplates(templateFS, subtree, destDir)
+	if err != nil {
+		return err
+	}
+	if subtree == "app" && appFramework != "none" {
+		log().Debug("applying app framework", "framework", appFramework, "path", destDir)
+		overDirs, overFiles, err := planTemplates(assets.Templates, "frameworks/"+appFramework, destDir)
+		if err != nil {
+			return err
+		}
+		dirs, files = overlayPlan(dirs, files, overDirs, overFiles)
+	}
+	return writePlan(dirs, files)
+}
+
+// overlayPlan adds the directories and files of an overlay plan to a base
+// plan. An overlay file takes the place of the base file with the same
+// destination; the others are added after the base files.
+func overlayPlan(dirs []string, files []fileOp, overDirs []string, overFiles []fileOp) ([]string, []fileOp) {
+	for _, dir := range overDirs {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	index := make(map[string]int, len(files))
+	for i, op := range files {
+		index[op.dest] = i
+	}
+	for _, op := range overFiles {
+		if i, ok := index[op.dest]; ok {
+			files[i] = op
+		} else {
+			files = append(files, op)
+		}
+	}
+	return dirs, files
+}
+
+// rootTemplateFiles returns the unlocalized names of the files at the top of
+// fsys, such as .gitignore and README.md. Directories are subtrees, copied by
+// createTemplates, and the groups and metadata manifests are not part of the
+// scaffold.
+func rootTemplateFiles(fsys fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == groupsFile || entry.Name() == templateMetaFile {
+			continue
+		}
+		name, _, _ := splitLocale(entry.Name())
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// copyRootTemplates copies every root-level file of fsys into baseDir.
+func copyRootTemplates(fsys fs.FS, baseDir string) error {
+	if structureOnly {
+		return nil
+	}
+	rootFiles, err := rootTemplateFiles(fsys)
+	if err != nil {
+		return err
+	}
+	for _, filename := range rootFiles {
+		srcPath, ok := localizedPath(fsys, ".", filename)
+		if !ok || excludedByGroup(srcPath) {
+			// Skip if file doesn't exist
+			continue
+		}
+		destPath := filepath.Join(baseDir, filename)
+
+		content, err := fs.ReadFile(fsys, srcPath)
+		if err != nil {
+			return err
+		}
+		content, err = renderTemplate(srcPath, content)
+		if err != nil {
+			return err
+		}
+
+		if err := createFile(destPath, content, templateMode(srcPath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileOp is a template file to render and write, or a symlink to recreate.
+type fileOp struct {
+	// fsys is the template tree src is read from.
+	fsys    fs.FS
+	src     string
+	dest    string
+	symlink bool
+}
+
+// writePlan creates the planned directories in order, parents before
+// children, and writes the planned files concurrently with copyTemplateFiles.
+func writePlan(dirs []string, files []fileOp) error {
+	var errs []error
+	for _, dir := range dirs {
+		if !keepGoing(&errs, createDirectory(dir)) {
+			return errors.Join(errs...)
+		}
+	}
+	files = slices.DeleteFunc(files, func(op fileOp) bool { return structureSkips(op.dest) })
+	errs = append(errs, copyTemplateFiles(files))
+	return errors.Join(errs...)
+}
+
+// planTemplates recursively lists the directories and files that copying
+// srcDir of fsys to destDir would create, skipping up-to-date directories
+// under --skip-existing-dir. Every template directory is planned, including
+// one holding only subdirectories or only a stripped .gitkeep.
+func planTemplates(fsys fs.FS, srcDir, destDir string) ([]string, []fileOp, error) {
+	if skipExistingDir && dirUpToDate(fsys, srcDir, destDir) {
+		log().Info("directory up to date, skipping", "path", destDir)
+		recordPath(&result.Skipped, destDir)
+		return nil, nil, nil
+	}
+
+	entries, err := fs.ReadDir(fsys, srcDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dirs []string
+	var files []fileOp
+	for _, entry := range entries {
+		srcPath, destPath, ok := templatePaths(fsys, srcDir, destDir, entry)
+		if !ok {
+			continue
+		}
+
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if !followSymlinks {
+				files = append(files, fileOp{fsys: fsys, src: srcPath, dest: destPath, symlink: true})
+				continue
+			}
+			info, err := fs.Stat(fsys, srcPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			isDir = info.IsDir()
+		}
+
+		if isDir {
+			subDirs, subFiles, err := planTemplates(fsys, srcPath, destPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			dirs = append(dirs, destPath)
+			dirs = append(dirs, subDirs...)
+			files = append(files, subFiles...)
+		} else {
+			files = append(files, fileOp{fsys: fsys, src: srcPath, dest: destPath})
+		}
+	}
+	return dirs, files, nil
+}
+
+// copyTemplateFiles renders and writes files using up to --workers
+// goroutines. The first error cancels the work that has not started yet and
+// is returned; under --continue-on-error every file is attempted and all
+// errors are returned.
+func copyTemplateFiles(files []fileOp) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := make(chan fileOp)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for range min(workers, len(files)) {
+		wg.Go(func() {
+			for op := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := copyTemplateFile(op); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					if !continueOnError {
+						cancel()
+					}
+				}
+			}
+		})
+	}
+
+	for _, op := range files {
+		select {
+		case jobs <- op:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if !continueOnError && len(errs) > 0 {
+		// Files already in flight may fail after the first; report the first
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// copyTemplateFile renders one template file and writes it to its destination.
+func copyTemplateFile(op fileOp) error {
+	if op.symlink {
+		target, err := fs.ReadLink(op.fsys, op.src)
+		if err != nil {
+			return err
+		}
+		return createSymlink(op.dest, target)
+	}
+	content, err := fs.ReadFile(op.fsys, op.src)
+	if err != nil {
+		return err
+	}
+	content, err = renderTemplate(op.src, content)
+	if err != nil {
+		return err
+	}
+	return createFile(op.dest, content, templateMode(op.src))
+}
+
+// dirUpToDate reports whether every template file under srcDir already exists
+// under destDir with identical content, so walking it would change nothing.
+func dirUpToDate(fsys fs.FS, srcDir, destDir string) bool {
+	entries, err := fs.ReadDir(fsys, srcDir)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		srcPath, destPath, ok := templatePaths(fsys, srcDir, destDir, entry)
+		if !ok {
+			continue
+		}
+
+		if entry.IsDir() {
+			if !dirUpToDate(fsys, srcPath, destPath) {
+				return false
+			}
+			continue
+		}
+		if entry.Type()&fs.ModeSymlink != 0 && !followSymlinks {
+			want, err := fs.ReadLink(fsys, srcPath)
+			if err != nil {
+				return false
+			}
+			if got, err := destFS.Readlink(destPath); err != nil || got != want {
+				return false
+			}
+			continue
+		}
+
+		want, err := fs.ReadFile(fsys, srcPath)
+		if err != nil {
+			return false
+		}
+		if want, err = renderTemplate(srcPath, want); err != nil {
+			return false
+		}
+		got, err := destFS.ReadFile(destPath)
+		if err != nil || !bytes.Equal(want, got) {
+			return false
+		}
+	}
+	return true
+}
+
+// projectPlaceholder is a template directory name replaced by the project's
+// Python package name, so a template can ship e.g. app/src/__project__.
+const projectPlaceholder = "__project__"
+
+// replacePlaceholder replaces projectPlaceholder segments of a slash-separated
+// template path with the project's package name.
+func replacePlaceholder(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		if part == projectPlaceholder {
+			parts[i] = moduleName(projectName())
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// templatePaths maps a template entry to its source and destination paths.
+// Localized file variants are written under their unlocalized name, and ok is
+// false for variants that lose to another variant of the same file, for
+// entries of optional groups not requested with --with, and for .gitkeep
+// placeholders under --strip-gitkeep.
+func templatePaths(fsys fs.FS, srcDir, destDir string, entry fs.DirEntry) (string, string, bool) {
+	srcPath := path.Join(srcDir, entry.Name())
+	if excludedByGroup(srcPath) {
+		return "", "", false
+	}
+	if entry.IsDir() {
+		name := destDirName(entry.Name())
+		if name != entry.Name() {
+			recordRename(filepath.Join(destDir, entry.Name()), filepath.Join(destDir, name))
+		}
+		return srcPath, filepath.Join(destDir, name), true
+	}
+
+	if stripGitkeep && slices.Contains(keepFiles, entry.Name()) {
+		return "", "", false
+	}
+	name, _, _ := splitLocale(entry.Name())
+	if selected, ok := localizedPath(fsys, srcDir, name); !ok || selected != srcPath {
+		return "", "", false
+	}
+	return srcPath, filepath.Join(destDir, name), true
+}
+
+// destDirName returns the name a template directory is written under: the
+// package name for projectPlaceholder, otherwise the --normalize-dir-names form.
+func destDirName(name string) string {
+	if name == projectPlaceholder {
+		return moduleName(projectName())
+	}
+	return normalizeDirName(name)
+}
+
+// templateRenames maps the slash-separated template path of every directory
+// written under another name to the path it is written to, for templates to
+// refer to as .Renamed.
+var templateRenames map[string]string
+
+// loadTemplateRenames fills templateRenames from the directories of
+// templateFS before any template is rendered.
+func loadTemplateRenames() error {
+	templateRenames = map[string]string{}
+	dest := map[string]string{".": "."}
+	return fs.WalkDir(templateFS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == "." || !d.IsDir() {
+			return err
+		}
+		if excludedByGroup(p) {
+			return fs.SkipDir
+		}
+		to := path.Join(dest[path.Dir(p)], destDirName(d.Name()))
+		dest[p] = to
+		if d.Name() != destDirName(d.Name()) {
+			templateRenames[p] = to
+		}
+		return nil
+	})
+}
+
+// recordRename notes a directory written under a normalized name.
+func recordRename(from, to string) {
+	if result.Renamed == nil {
+		result.Renamed = map[string]string{}
+	}
+	if _, ok := result.Renamed[from]; !ok {
+		log().Debug("directory name normalized", "from", from, "to", to)
+		result.Renamed[from] = to
+	}
+}
+
+// splitLocale splits a localized template name like README.ja.md into its
+// unlocalized name (README.md) and locale (ja).
+func splitLocale(name string) (string, string, bool) {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	loc := strings.TrimPrefix(path.Ext(stem), ".")
+	if loc == "" || !slices.Contains(locales, loc) {
+		return name, "", false
+	}
+	return strings.TrimSuffix(stem, "."+loc) + ext, loc, true
+}
+
+// localizedPath picks the template source for an unlocalized name in dir of fsys,
+// preferring the chosen locale, then the default locale, then the plain file.
+func localizedPath(fsys fs.FS, dir, name string) (string, bool) {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	candidates := []string{
+		path.Join(dir, stem+"."+locale+ext),
+		path.Join(dir, stem+"."+defaultLocale+ext),
+		path.Join(dir, name),
+	}
+	for _, candidate := range candidates {
+		if _, err := fs.Stat(fsys, candidate); err == nil {
+			return candidate, true
+		}
+	}
+	return "", false
 }
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRerunRecordsEachFileOnce(t *testing.T) {
+	chdirTemp(t)
+	first := mustExecute(t, "create", "--name", "p", "--no-tree")
+	files := len(first.Created) - len(first.Directories)
+
+	second := mustExecute(t, "create", "--name", "p", "--no-tree")
+	if len(second.Unchanged) != files {
+		t.Errorf("rerun recorded %d unchanged files, want %d", len(second.Unchanged), files)
+	}
+	sorted := slices.Sorted(slices.Values(second.Unchanged))
+	if compacted := slices.Compact(slices.Clone(sorted)); len(compacted) != len(sorted) {
+		t.Errorf("rerun recorded a file more than once: %v", sorted)
+	}
+}
+
+func TestCreateRerunRecordsEachFileOnce(t *testing.T) {
+	dir := chdirTemp(t)
+	first, err := Create(CreateOptions{Name: "p", TargetDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Create(CreateOptions{Name: "p", TargetDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(second.Unchanged), len(first.Created)-len(first.Directories); got != want {
+		t.Errorf("rerun recorded %d unchanged files, want %d", got, want)
+	}
+}
+
+func TestDirectoriesWithoutFilesAreCreated(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":                "readme",
+		"tpl/app/nested/only/.gitkeep": "",
+		"tpl/app/src/main.py":          "",
+		"tpl/infra/placeholder/.keep":  "",
+	})
+
+	mustExecute(t, "create", "--name", "kept", "--template-dir", "tpl", "--no-tree")
+	// app/nested holds only a subdirectory
+	assertExists(t, "kept", "app/nested", "app/nested/only/.gitkeep", "infra/placeholder/.keep")
+
+	mustExecute(t, "create", "--name", "stripped", "--template-dir", "tpl", "--strip-gitkeep", "--no-tree")
+	assertExists(t, "stripped", "app/nested/only", "infra/placeholder")
+	assertMissing(t, "stripped", "app/nested/only/.gitkeep", "infra/placeholder/.keep")
+}
+
+func TestTemplateDir(t *testing.T) {
+	tpl, err := filepath.Abs("testdata/tpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chdirTemp(t)
+	mustExecute(t, "create", "--name", "p", "--template-dir", tpl, "--no-tree")
+	assertExists(t, "p", "README.md", "web/index.html")
+	if got, err := os.ReadFile(filepath.Join("p", "README.md")); err != nil || string(got) != "hi p\n" {
+		t.Errorf("README.md = %q, %v; want the rendered template", got, err)
+	}
+	assertMissing(t, "p", "app", "infra")
+}
+
+func TestTemplateDirMustBeADirectory(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{"file": ""})
+	for _, tpl := range []string{"missing", "file"} {
+		if _, err := execute(t, "create", "--name", "p", "--template-dir", tpl); err == nil {
+			t.Errorf("--template-dir %s: want an error", tpl)
+		}
+		assertMissing(t, dir, "p")
+	}
+}
+
+func TestScriptsAreExecutable(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":               "readme",
+		"tpl/app/scripts/setup.py":    "print()",
+		"tpl/app/scripts/__init__.py": "",
+		"tpl/app/tools/bin/run":       "run",
+		"tpl/app/src/main.py":         "",
+	})
+	mustExecute(t, "create", "--name", "p", "--template-dir", "tpl", "--no-tree")
+	for name, want := range map[string]os.FileMode{
+		"app/scripts/setup.py":    0755,
+		"app/tools/bin/run":       0755,
+		"app/scripts/__init__.py": 0644,
+		"app/src/main.py":         0644,
+	} {
+		info, err := os.Stat(filepath.Join("p", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s: mode = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestProjectPlaceholderIsRenamed(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":                         "readme",
+		"tpl/app/src/__project__/__init__.py":   "",
+		"tpl/app/src/__project__/cli.py":        "",
+		"tpl/app/tests/__project__/test_cli.py": "",
+	})
+	mustExecute(t, "create", "--name", "My-App", "--template-dir", "tpl", "--no-tree")
+	assertExists(t, "My-App",
+		"app/src/my_app/__init__.py",
+		"app/src/my_app/cli.py",
+		"app/tests/my_app/test_cli.py",
+	)
+	err := filepath.WalkDir("My-App", func(p string, d os.DirEntry, err error) error {
+		if err == nil && d.Name() == projectPlaceholder {
+			t.Errorf("%s was not renamed", p)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFrameworkRerunIsUnchanged(t *testing.T) {
+	for _, framework := range []string{"fastapi", "flask"} {
+		t.Run(framework, func(t *testing.T) {
+			chdirTemp(t)
+			first := mustExecute(t, "create", "--name", "p", "--app-framework", framework, "--no-tree")
+			got, err := os.ReadFile(filepath.Join("p", "app", "pyproject.toml"))
+			if err != nil || !strings.Contains(string(got), `"`+framework+`>=`) {
+				t.Errorf("app/pyproject.toml = %q, %v; want the %s version", got, err, framework)
+			}
+
+			second := mustExecute(t, "create", "--name", "p", "--app-framework", framework, "--no-tree")
+			files := len(first.Created) - len(first.Directories)
+			if len(second.Unchanged) != files || len(second.Overwritten) != 0 {
+				t.Errorf("rerun: %d unchanged and %d overwritten, want %d and 0", len(second.Unchanged), len(second.Overwritten), files)
+			}
+		})
+	}
+}
+
+func TestStatsDryRun(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":       "readme",
+		"tpl/app/src/main.py": "print()",
+		"tpl/app/src/util.py": "x = 1",
+	})
+	args := []string{"create", "--name", "p", "--template-dir", "tpl", "--stats", "--no-tree"}
+	dry := captureStdout(t, func() { mustExecute(t, append(args, "--dry-run")...) })
+	assertMissing(t, dir, "p")
+	if !strings.Contains(dry, ".py: 3 files") || !strings.Contains(dry, ".md: 1 files") {
+		t.Errorf("--dry-run --stats printed %q, want the counts of the planned files", dry)
+	}
+
+	real := captureStdout(t, func() { mustExecute(t, args...) })
+	if real != dry {
+		t.Errorf("--stats printed %q, but --dry-run --stats printed %q", real, dry)
+	}
+}
+
+func TestDestinationPathsUseOSSeparator(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/README.md":      {},
+		"app/src/pkg/mod.py": {},
+	}
+	root := filepath.Join("out", "p", "app")
+	dirs, files, err := planTemplates(fsys, "app", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(root, "src"), filepath.Join(root, "src", "pkg")}; !slices.Equal(dirs, want) {
+		t.Errorf("dirs = %q, want %q", dirs, want)
+	}
+	want := map[string]string{
+		// Template paths stay slash-separated, as fs.FS requires
+		"app/README.md":      filepath.Join(root, "README.md"),
+		"app/src/pkg/mod.py": filepath.Join(root, "src", "pkg", "mod.py"),
+	}
+	if len(files) != len(want) {
+		t.Errorf("planned %d files, want %d", len(files), len(want))
+	}
+	for _, op := range files {
+		if op.dest != want[op.src] {
+			t.Errorf("%s: dest %q, want %q", op.src, op.dest, want[op.src])
+		}
+	}
+
+	// Every path of a real run, including the root files and the Python
+	// markers, is a clean OS path
+	chdirTemp(t)
+	res := mustExecute(t, "create", "--name", "p", "--no-tree")
+	for _, p := range res.Created {
+		if p != filepath.Clean(p) || (filepath.Separator != '/' && strings.Contains(p, "/")) {
+			t.Errorf("created %q, want a clean path with %q separators", p, filepath.Separator)
+		}
+	}
+	assertExists(t, ".", "p/README.md", "p/app/tests/__init__.py")
+}
+
+func TestTimeBudgetSkipsOptionalSteps(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/app/go.mod":      "module app\n",
+		"tpl/app/app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) { t.Fatal(\"ran\") }\n",
+	})
+	mustExecute(t, "create", "--name", "p", "--template-dir", "tpl", "--stack", "go", "--time-budget", "1ns",
+		"--checksums", "p/SHA256SUMS", "--validate-output", "--run-tests", "--git", "--no-tree")
+	assertExists(t, dir, "p/app/go.mod")
+	assertMissing(t, dir, "p/SHA256SUMS", "p/.git")
+	for _, step := range []string{"checksums", "validate-output", "run-tests", "git"} {
+		if !budgetSkipped[step] {
+			t.Errorf("%s was not skipped", step)
+		}
+	}
+}
+
+func TestDryRunSkipsDiskSteps(t *testing.T) {
+	dir := chdirTemp(t)
+	mustExecute(t, "create", "--name", "p", "--dry-run", "--checksums", "p/SHA256SUMS",
+		"--run-tests", "--validate-output", "--git-branch", "scaffold", "--no-tree")
+	assertMissing(t, dir, "p")
+}
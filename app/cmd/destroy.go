@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// destroyName is the project directory to remove.
+var destroyName string
+
+// destroyYes skips the confirmation prompt.
+var destroyYes bool
+
+// destroyCmd represents the destroy command
+var destroyCmd = &cobra.Command{
+	Use:     "destroy",
+	Aliases: []string{"clean"},
+	Short:   "Remove a project directory created by appinit",
+	Long: `Remove a project directory under the current directory after checking that it
+is an appinit project: the .appinit.json marker create writes, and a README.md or
+repo.code-workspace next to an app or infra directory. Asks for confirmation unless --yes is given, which is
+required when stdin is not a terminal.
+Example: appinit destroy --name my-app
+Example: appinit destroy --name my-app --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		dir, err := destroyTarget(destroyName)
+		if err != nil {
+			return err
+		}
+		if !destroyYes {
+			if !stdinIsTerminal() {
+				return errors.New("stdin is not a terminal; pass --yes to confirm the removal")
+			}
+			ok, err := confirm(os.Stdin, os.Stderr, fmt.Sprintf("Remove %s and everything in it? [y/N]: ", dir))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				log().Info("nothing removed", "path", dir)
+				return nil
+			}
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		log().Info("project removed", "path", dir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(destroyCmd)
+	destroyCmd.Flags().StringVar(&destroyName, "name", "", "Project directory to remove, relative to the current directory")
+	destroyCmd.Flags().BoolVarP(&destroyYes, "yes", "y", false, "Remove without asking for confirmation")
+	cobra.CheckErr(destroyCmd.MarkFlagRequired("name"))
+	registerFlagCompletions(destroyCmd)
+}
+
+// destroyTarget resolves name to a directory strictly below the current
+// directory and checks that it looks like an appinit project.
+func destroyTarget(name string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if cwd, err = filepath.EvalSymlinks(cwd); err != nil {
+		return "", err
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(cwd, name))
+	if err != nil {
+		return "", fmt.Errorf("invalid --name: %w", err)
+	}
+	rel, err := filepath.Rel(cwd, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("refusing to remove %s: only directories below the current directory can be destroyed", dir)
+	}
+	if !looksLikeProject(dir) {
+		return "", fmt.Errorf("refusing to remove %s: it does not look like an appinit project (no %s, or no README.md or %s next to an app or infra directory)", dir, projectMarkerFile, workspaceFile)
+	}
+	return dir, nil
+}
+
+// looksLikeProject reports whether dir has the project marker, the root files,
+// and at least one of the subtrees appinit creates.
+func looksLikeProject(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, projectMarkerFile)); err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	rootFile := false
+	for _, name := range []string{"README.md", workspaceFile} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.Mode().IsRegular() {
+			rootFile = true
+		}
+	}
+	for _, name := range []string{"app", "infra"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() && rootFile {
+			return true
+		}
+	}
+	return false
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprint(out, question)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return false, fmt.Errorf("read answer: %w", err)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDestroy(t *testing.T) {
+	dir := chdirTemp(t)
+	mustExecute(t, "create", "--name", "p", "--no-tree")
+	assertExists(t, dir, "p/"+projectMarkerFile)
+
+	mustExecute(t, "destroy", "--name", "p", "--yes")
+	assertMissing(t, dir, "p")
+}
+
+func TestDestroyRefuses(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		// The layout of a project, but not created by appinit
+		"lookalike/README.md":   "readme",
+		"lookalike/app/main.py": "",
+	})
+	mustExecute(t, "create", "--name", "p", "--no-tree")
+
+	tests := []struct {
+		name, wantErr string
+	}{
+		{"lookalike", "does not look like an appinit project"},
+		{".", "only directories below the current directory"},
+		{"..", "only directories below the current directory"},
+		{"p/..", "only directories below the current directory"},
+		{"missing", "invalid --name"},
+	}
+	for _, tt := range tests {
+		_, err := execute(t, "destroy", "--name", tt.name, "--yes")
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("destroy --name %s: err = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+	assertExists(t, dir, "lookalike/README.md", "p/"+projectMarkerFile)
+}
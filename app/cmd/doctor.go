@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// requiredTemplateDirs are the embedded directories create cannot work without.
+var requiredTemplateDirs = []string{"templates", "templates/app", "templates/infra"}
+
+// requiredRootFiles are the root templates other commands read or look for by
+// name, on top of whatever copyRootTemplates discovers.
+var requiredRootFiles = []string{"README.md", workspaceFile}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	Aliases: []string{"self-test"},
+	Short:   "Check that the embedded templates of this binary are intact",
+	Long: `Check the templates embedded in this binary: the templates, templates/app, and
+templates/infra directories, the root files create copies, and that every
+embedded file can be read. Prints a pass/fail line per check and exits non-zero
+if any check fails. Nothing is written to disk.
+Example: appinit doctor`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		checks := checkEmbeddedTemplates(assets.Templates)
+		failed := 0
+		for _, c := range checks {
+			if c.err != nil {
+				failed++
+				fmt.Printf("FAIL %s: %v\n", c.name, c.err)
+			} else {
+				fmt.Printf("PASS %s\n", c.name)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctorCheck is the outcome of one doctor check; err is nil when it passed.
+type doctorCheck struct {
+	name string
+	err  error
+}
+
+// checkEmbeddedTemplates runs the doctor checks against fsys, which has the
+// layout of assets.Templates.
+func checkEmbeddedTemplates(fsys fs.FS) []doctorCheck {
+	var checks []doctorCheck
+	for _, dir := range requiredTemplateDirs {
+		check := doctorCheck{name: dir + "/"}
+		if info, err := fs.Stat(fsys, dir); err != nil {
+			check.err = err
+		} else if !info.IsDir() {
+			check.err = fmt.Errorf("not a directory")
+		}
+		checks = append(checks, check)
+	}
+
+	if sub, err := fs.Sub(fsys, "templates"); err == nil {
+		rootFiles, err := rootTemplateFiles(sub)
+		if err != nil {
+			checks = append(checks, doctorCheck{name: "root files", err: err})
+		}
+		for _, name := range requiredRootFiles {
+			if !slices.Contains(rootFiles, name) {
+				rootFiles = append(rootFiles, name)
+			}
+		}
+		for _, name := range rootFiles {
+			check := doctorCheck{name: path.Join("templates", name)}
+			if _, ok := localizedPath(sub, ".", name); !ok {
+				check.err = fs.ErrNotExist
+			}
+			checks = append(checks, check)
+		}
+	}
+
+	files, unreadable := 0, 0
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			checks = append(checks, doctorCheck{name: p, err: err})
+			unreadable++
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		files++
+		if _, err := fs.ReadFile(fsys, p); err != nil {
+			checks = append(checks, doctorCheck{name: p, err: err})
+			unreadable++
+		}
+		return nil
+	})
+	check := doctorCheck{name: fmt.Sprintf("%d embedded files readable", files-unreadable)}
+	if err != nil {
+		check.err = err
+	} else if unreadable > 0 {
+		check.err = fmt.Errorf("%d entries unreadable", unreadable)
+	}
+	return append(checks, check)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os/exec"
+)
+
+// quietErrorsAsJSON reports failures as a JSON object on stderr instead of a log line.
+var quietErrorsAsJSON bool
+
+// errorExitCode is the process exit code for a failed run.
+const errorExitCode = 1
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&quietErrorsAsJSON, "quiet-errors-as-json", false, "On failure, write a JSON error object to stderr instead of a log line")
+}
+
+// errorReport is the machine-readable description of a failed run.
+type errorReport struct {
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Path     string `json:"path,omitempty"`
+	ExitCode int    `json:"exit_code"`
+	RunID    string `json:"run_id,omitempty"`
+}
+
+// errorsAsJSON reports whether failures should be written as JSON: when
+// requested explicitly or when the command's output format is JSON.
+func errorsAsJSON() bool {
+	return quietErrorsAsJSON || compareFormat == "json"
+}
+
+// newErrorReport classifies err for tooling.
+func newErrorReport(err error) errorReport {
+	report := errorReport{Type: "error", Message: err.Error(), ExitCode: errorExitCode, RunID: runID}
+
+	var pathErr *fs.PathError
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &pathErr):
+		report.Type = "filesystem"
+		report.Path = pathErr.Path
+	case errors.As(err, &exitErr):
+		report.Type = "subprocess"
+	}
+	return report
+}
+
+// writeErrorJSON writes a single-line JSON error object for err to w.
+func writeErrorJSON(w io.Writer, err error) {
+	data, marshalErr := json.Marshal(newErrorReport(err))
+	if marshalErr != nil {
+		data, _ = json.Marshal(errorReport{Type: "error", Message: err.Error(), ExitCode: errorExitCode})
+	}
+	w.Write(append(data, '\n'))
+}
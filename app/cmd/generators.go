@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// makefile generates a stack-appropriate Makefile in the project root.
+var makefile bool
+
+// preCommit generates a stack-appropriate .pre-commit-config.yaml in the project root.
+var preCommit bool
+
+// scaffoldedProjects returns the project directories this run scaffolds,
+// relative to the target directory.
+func scaffoldedProjects() []string {
+	switch {
+	case appOnly:
+		return []string{"app"}
+	case infraOnly:
+		return []string{"infra"}
+	case rootOnly:
+		return nil
+	default:
+		subtrees, _ := selectedSubtrees()
+		return subtrees
+	}
+}
+
+// templateStack returns the stack of an embedded template subtree, detected
+// from the same marker files the workspace command looks for.
+func templateStack(subtree string) (string, bool) {
+	for _, marker := range stackMarkers {
+		if _, err := fs.Stat(templateFS, subtree+"/"+marker.file); err == nil {
+			return marker.stack, true
+		}
+	}
+	return "", false
+}
+
+// projectStack returns the --stack value if given, otherwise the single stack
+// detected across the scaffolded projects.
+func projectStack() (string, error) {
+	if stackExplicit {
+		return stack, nil
+	}
+	var stacks []string
+	for _, project := range scaffoldedProjects() {
+		if stack, ok := templateStack(project); ok && !slices.Contains(stacks, stack) {
+			stacks = append(stacks, stack)
+		}
+	}
+	if len(stacks) != 1 {
+		return "", fmt.Errorf("projects of a single stack are required, found %d", len(stacks))
+	}
+	return stacks[0], nil
+}
+
+// writeGenerated renders the named generator for the projects' stack into
+// the target directory as dest. Generators are stored without a leading dot,
+// which go:embed would leave out.
+func writeGenerated(flag, name, dest string) error {
+	stack, err := projectStack()
+	if err != nil {
+		return fmt.Errorf("--%s: %w", flag, err)
+	}
+
+	srcPath := "generators/" + stack + "/" + name
+	content, err := assets.Templates.ReadFile(srcPath)
+	if err != nil {
+		return fmt.Errorf("--%s does not support the %s stack", flag, stack)
+	}
+	content, err = renderTemplate(srcPath, content)
+	if err != nil {
+		return err
+	}
+	return createFile(filepath.Join(targetDir(), dest), content, 0644)
+}
+
+// installPreCommit runs pre-commit install in the target when the tool is
+// available and the target is the root of a git work tree; otherwise it is
+// skipped, so a project inside another repository never installs hooks into
+// the enclosing one.
+func installPreCommit() error {
+	if _, err := exec.LookPath("pre-commit"); err != nil {
+		log().Info("pre-commit not found on PATH, skipping hook install")
+		return nil
+	}
+	dir := targetDir()
+	if !isGitRoot(dir) {
+		log().Info("not the root of a git repository, skipping pre-commit hook install", "path", dir)
+		return nil
+	}
+
+	cmd := exec.Command("pre-commit", "install")
+	cmd.Dir = dir
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pre-commit install: %w", err)
+	}
+	return nil
+}
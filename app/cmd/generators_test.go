@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakePreCommit puts a pre-commit on PATH that records the directory it was
+// run in, and returns the file it records to.
+func fakePreCommit(t *testing.T, dir string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake pre-commit is a shell script")
+	}
+	requireGit(t)
+	bin := filepath.Join(dir, "bin")
+	record := filepath.Join(dir, "pre-commit-ran")
+	writeTree(t, bin, map[string]string{"pre-commit": "#!/bin/sh\npwd >> " + record + "\n"})
+	if err := os.Chmod(filepath.Join(bin, "pre-commit"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return record
+}
+
+func TestPreCommitInstallsIntoNewRepository(t *testing.T) {
+	dir := chdirTemp(t)
+	record := fakePreCommit(t, dir)
+	mustExecute(t, "create", "--name", "p", "--git", "--pre-commit", "--no-tree")
+
+	assertExists(t, dir, "p/.git", "p/.pre-commit-config.yaml")
+	got, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatalf("pre-commit install did not run: %v", err)
+	}
+	if ran := strings.TrimSpace(string(got)); !sameDir(ran, filepath.Join(dir, "p")) {
+		t.Errorf("pre-commit install ran in %s, want the project root", ran)
+	}
+}
+
+func TestPreCommitSkipsEnclosingRepository(t *testing.T) {
+	dir := chdirTemp(t)
+	record := fakePreCommit(t, dir)
+	if _, err := runGit(dir, "init", "--quiet"); err != nil {
+		t.Fatal(err)
+	}
+	mustExecute(t, "create", "--name", "p", "--pre-commit", "--no-tree")
+
+	assertExists(t, dir, "p/.pre-commit-config.yaml")
+	if got, err := os.ReadFile(record); err == nil {
+		t.Errorf("pre-commit install ran in %s, inside the enclosing repository", strings.TrimSpace(string(got)))
+	}
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// runGit runs git with the given arguments in dir and returns its trimmed stdout.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// inGitRepo reports whether dir is inside a git work tree.
+func inGitRepo(dir string) bool {
+	if _, err := exec.LookPath("git"); err != nil {
+		warn("git not found on PATH", "error", err)
+		return false
+	}
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// isGitRoot reports whether dir is the top level of a git work tree rather
+// than a directory somewhere inside one.
+func isGitRoot(dir string) bool {
+	if !inGitRepo(dir) {
+		return false
+	}
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return false
+	}
+	return sameDir(top, dir)
+}
+
+// sameDir reports whether a and b name the same directory once symlinks are resolved.
+func sameDir(a, b string) bool {
+	a, errA := filepath.EvalSymlinks(a)
+	b, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	a, errA = filepath.Abs(a)
+	b, errB = filepath.Abs(b)
+	return errA == nil && errB == nil && a == b
+}
+
+// checkCleanGit returns an error if dir is in a git work tree with uncommitted changes.
+func checkCleanGit(dir string) error {
+	if !inGitRepo(dir) {
+		log().Debug("not a git repository, skipping dirty check", "path", dir)
+		return nil
+	}
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if status != "" {
+		return fmt.Errorf("git work tree at %s has uncommitted changes; commit or stash them first", dir)
+	}
+	return nil
+}
+
+// runCreateOnBranch scaffolds on the git branch gitBranch of the repository
+// containing the target, optionally commits the result, and then switches
+// back to the branch that was checked out before.
+func runCreateOnBranch() error {
+	dir := gitCheckDir()
+	if !inGitRepo(dir) {
+		return fmt.Errorf("--git-branch requires %s to be inside a git repository", dir)
+	}
+	if !force {
+		if err := checkCleanGit(dir); err != nil {
+			return fmt.Errorf("%w, or use --force to scaffold over them", err)
+		}
+	}
+
+	previous, err := runGit(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return err
+	}
+	if previous == "HEAD" {
+		// Detached HEAD: return to the commit itself
+		if previous, err = runGit(dir, "rev-parse", "HEAD"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+gitBranch); err == nil {
+		if _, err := runGit(dir, "checkout", gitBranch); err != nil {
+			return err
+		}
+	} else if _, err := runGit(dir, "checkout", "-b", gitBranch); err != nil {
+		return err
+	}
+	log().Info("switched to branch", "branch", gitBranch)
+
+	createErr := runCreate()
+	if files := generatedFiles(); createErr == nil && gitCommit {
+		if len(files) == 0 {
+			log().Info("scaffold unchanged, nothing to commit", "branch", gitBranch)
+		} else {
+			// Only the scaffold: under --force the tree may hold unrelated changes
+			createErr = commitScaffold(dir, files...)
+		}
+	}
+
+	if _, err := runGit(dir, "checkout", previous); err != nil {
+		return errors.Join(createErr, fmt.Errorf("restore branch %s: %w", previous, err))
+	}
+	log().Info("restored previous branch", "branch", previous)
+	if createErr == nil && !gitCommit {
+		warn("scaffold was not committed and remains in the working tree; use --git-commit to keep it on the branch", "branch", gitBranch)
+	}
+	return createErr
+}
+
+// commitScaffold commits files, or everything under dir when no files are
+// given, as the scaffold commit.
+func commitScaffold(dir string, files ...string) error {
+	pathspec := []string{"."}
+	if len(files) > 0 {
+		pathspec = pathspec[:0]
+		for _, f := range files {
+			abs, err := filepath.Abs(f)
+			if err != nil {
+				return err
+			}
+			pathspec = append(pathspec, abs)
+		}
+	}
+	if _, err := runGit(dir, append([]string{"add", "-A", "--"}, pathspec...)...); err != nil {
+		return err
+	}
+	if _, err := runGit(dir, append([]string{"commit", "-m", "Scaffold project with appinit", "--"}, pathspec...)...); err != nil {
+		return err
+	}
+	log().Info("scaffold committed", "path", dir)
+	return nil
+}
+
+// initGitRepo runs git init in dir and commits the scaffold. The scaffold has
+// already succeeded by then, so failures are reported as warnings.
+func initGitRepo(dir string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		warn("git not found on PATH, skipping git init", "error", err)
+		return
+	}
+	if inGitRepo(dir) {
+		warn("target is already inside a git repository, skipping git init", "path", dir)
+		return
+	}
+	if _, err := runGit(dir, "init"); err != nil {
+		warn("git init failed", "path", dir, "error", err)
+		return
+	}
+	if err := commitScaffold(dir); err != nil {
+		warn("initial commit failed", "path", dir, "error", err)
+		return
+	}
+	log().Info("git repository initialized", "path", dir)
+}
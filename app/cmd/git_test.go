@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// dirtyRepo makes dir a git repository with an uncommitted file.
+func dirtyRepo(t *testing.T, dir string) {
+	t.Helper()
+	requireGit(t)
+	writeTree(t, dir, map[string]string{"tracked.txt": "v1"})
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "."}, {"commit", "--quiet", "-m", "init"}} {
+		if _, err := runGit(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTree(t, dir, map[string]string{"tracked.txt": "v2"})
+}
+
+func TestAbortOnDirtyGit(t *testing.T) {
+	dir := chdirTemp(t)
+	dirtyRepo(t, dir)
+
+	_, err := execute(t, "create", "--name", "p", "--abort-on-dirty-git", "--no-tree")
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("err = %v, want an uncommitted changes error", err)
+	}
+	assertMissing(t, dir, "p")
+
+	mustExecute(t, "create", "--name", "p", "--abort-on-dirty-git", "--force", "--no-tree")
+	assertExists(t, dir, "p/README.md")
+}
+
+func TestGitBranchDirtyTree(t *testing.T) {
+	dir := chdirTemp(t)
+	dirtyRepo(t, dir)
+
+	_, err := execute(t, "create", "--name", "p", "--git-branch", "scaffold", "--git-commit", "--no-tree")
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("err = %v, want an error pointing at --force", err)
+	}
+	assertMissing(t, dir, "p")
+
+	mustExecute(t, "create", "--name", "p", "--git-branch", "scaffold", "--git-commit", "--force", "--no-tree")
+	files, err := runGit(dir, "show", "--name-only", "--format=", "scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(files, "p/README.md") || strings.Contains(files, "tracked.txt") {
+		t.Errorf("scaffold commit has files %q, want only the scaffold", files)
+	}
+	if status, err := runGit(dir, "status", "--porcelain", "tracked.txt"); err != nil || status == "" {
+		t.Errorf("uncommitted change to tracked.txt was lost: %q, %v", status, err)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// groupsFile is the manifest at the templates root that declares optional groups.
+const groupsFile = "groups.yaml"
+
+// withGroups are the optional template groups to include.
+var withGroups []string
+
+// templateGroups maps each optional group of the current templates to the
+// template paths it covers.
+var templateGroups map[string][]string
+
+// loadTemplateGroups reads the groups manifest of templateFS, if it has one.
+func loadTemplateGroups() error {
+	templateGroups = map[string][]string{}
+	content, err := fs.ReadFile(templateFS, groupsFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return fmt.Errorf("invalid %s: %w", groupsFile, err)
+	}
+	for _, group := range v.AllKeys() {
+		for _, p := range v.GetStringSlice(group) {
+			templateGroups[group] = append(templateGroups[group], path.Clean(p))
+		}
+	}
+	return nil
+}
+
+// groupNames returns the sorted names of the current templates' groups.
+func groupNames() []string {
+	names := make([]string, 0, len(templateGroups))
+	for name := range templateGroups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// checkWithGroups returns an error for a --with name the templates do not define.
+func checkWithGroups() error {
+	for _, name := range withGroups {
+		if _, ok := templateGroups[name]; ok {
+			continue
+		}
+		if len(templateGroups) == 0 {
+			return fmt.Errorf("unknown --with group %q: the templates define no groups", name)
+		}
+		return fmt.Errorf("unknown --with group %q (available: %s)", name, strings.Join(groupNames(), ", "))
+	}
+	return nil
+}
+
+// excludedByGroup reports whether a template path belongs to a group that
+// was not requested with --with.
+func excludedByGroup(srcPath string) bool {
+	for group, paths := range templateGroups {
+		if slices.Contains(withGroups, group) {
+			continue
+		}
+		for _, p := range paths {
+			if srcPath == p || strings.HasPrefix(srcPath, p+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// chdirTemp switches the test into an empty temporary directory, which also
+// serves as HOME so no config file or template cache of the user is read.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", dir)
+	return dir
+}
+
+// execute runs appinit with args in the current directory with logging discarded.
+func execute(t *testing.T, args ...string) (Result, error) {
+	t.Helper()
+	logger = slog.New(slog.DiscardHandler)
+	t.Cleanup(func() { logger = nil })
+	return ExecuteContext(context.Background(), args)
+}
+
+// mustExecute is execute for runs that must succeed.
+func mustExecute(t *testing.T, args ...string) Result {
+	t.Helper()
+	res, err := execute(t, args...)
+	if err != nil {
+		t.Fatalf("appinit %v: %v", args, err)
+	}
+	return res
+}
+
+// writeTree creates files under root from slash-separated relative paths.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// assertExists fails unless every slash-separated path exists under root.
+func assertExists(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		if _, err := os.Lstat(filepath.Join(root, filepath.FromSlash(p))); err != nil {
+			t.Errorf("%s: %v", p, err)
+		}
+	}
+}
+
+// assertMissing fails if any slash-separated path exists under root.
+func assertMissing(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		if _, err := os.Lstat(filepath.Join(root, filepath.FromSlash(p))); err == nil {
+			t.Errorf("%s exists", p)
+		}
+	}
+}
+
+// requireGit skips the test unless git is on PATH, and gives git an identity
+// so the test can commit.
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found on PATH")
+	}
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+}
+
+// captureStdout returns what f writes to os.Stdout.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	defer func() { os.Stdout = previous }()
+	f()
+	w.Close()
+	return string(<-done)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// interactive prompts for the project name, areas, and license instead of requiring flags.
+var interactive bool
+
+// stdinIsTerminal reports whether stdin is an interactive terminal rather than a pipe or file.
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// wantInteractive reports whether create should prompt: when asked with
+// --interactive, or when run from a terminal without any flags.
+func wantInteractive(flags *pflag.FlagSet) (bool, error) {
+	if interactive {
+		if !stdinIsTerminal() {
+			return false, errors.New("--interactive needs a terminal on stdin; pass --name and the other flags instead")
+		}
+		return true, nil
+	}
+	return flags.NFlag() == 0 && stdinIsTerminal(), nil
+}
+
+// promptCreateFlags asks for the project name, which areas to include, and a
+// license, and sets the matching flags. Invalid answers are asked again.
+func promptCreateFlags(flags *pflag.FlagSet, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	ask := func(question string) (string, error) {
+		fmt.Fprint(out, question)
+		line, err := reader.ReadString('\n')
+		if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+			return "", fmt.Errorf("read answer: %w", err)
+		}
+		return strings.TrimSpace(line), nil
+	}
+
+	for {
+		name, err := ask("Project name: ")
+		if err != nil {
+			return err
+		}
+		if name, err = normalizeAppName(name); err == nil {
+			err = validateAppName(name)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+		if err := flags.Set("name", name); err != nil {
+			return err
+		}
+		break
+	}
+
+	for {
+		areas, err := ask("Include app, infra, or both? [both]: ")
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(areas) {
+		case "", "both":
+		case "app", "infra":
+			if err := flags.Set("only", strings.ToLower(areas)); err != nil {
+				return err
+			}
+		default:
+			fmt.Fprintln(out, "please answer app, infra, or both")
+			continue
+		}
+		break
+	}
+
+	supported := licenses()
+	for {
+		answer, err := ask(fmt.Sprintf("License (%s, or none) [none]: ", strings.Join(supported, ", ")))
+		if err != nil {
+			return err
+		}
+		if answer == "" || strings.EqualFold(answer, "none") {
+			return nil
+		}
+		for _, id := range supported {
+			if strings.EqualFold(answer, id) {
+				return flags.Set("license", id)
+			}
+		}
+		fmt.Fprintf(out, "unsupported license %q\n", answer)
+	}
+}
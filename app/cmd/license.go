@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// license is the SPDX identifier of the LICENSE file to write; empty writes none.
+var license string
+
+// licenseAuthor is the copyright holder substituted into the license text.
+var licenseAuthor string
+
+// licenses returns the SPDX identifiers of the embedded license texts.
+func licenses() []string {
+	entries, err := fs.ReadDir(assets.Templates, "licenses")
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.Name())
+	}
+	return ids
+}
+
+// checkLicense returns an error if --license is not an embedded license.
+func checkLicense() error {
+	if license == "" || slices.Contains(licenses(), license) {
+		return nil
+	}
+	return fmt.Errorf("unsupported --license %q (supported: %s)", license, strings.Join(licenses(), ", "))
+}
+
+// writeLicense writes the --license text, with the year and --author filled
+// in, to LICENSE in root.
+func writeLicense(root string) error {
+	if license == "" {
+		return nil
+	}
+	srcPath := "licenses/" + license
+	content, err := fs.ReadFile(assets.Templates, srcPath)
+	if err != nil {
+		return err
+	}
+	if content, err = renderTemplate(srcPath, content); err != nil {
+		return err
+	}
+	return createFile(filepath.Join(root, "LICENSE"), content, 0644)
+}
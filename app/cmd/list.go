@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the structure the built-in templates create",
+	Long: `Print the files and directories create would generate, grouped the way
+create treats them: the root-level files, then each template subtree with the
+mode that writes it. Nothing is written to disk.
+Example: appinit list`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return printTemplateLayout("")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
+
+// printTemplateLayout prints what templateFS scaffolds: the root-level files,
+// then each template subtree with the mode that writes it, every line
+// prefixed with indent.
+func printTemplateLayout(indent string) error {
+	rootFiles, err := rootTemplateFiles(templateFS)
+	if err != nil {
+		return err
+	}
+	fmt.Println(indent + "root files (create --name, --root-only):")
+	for _, name := range rootFiles {
+		if _, ok := localizedPath(templateFS, ".", name); ok {
+			fmt.Println(indent + "  " + name)
+		}
+	}
+
+	subtrees, err := templateSubtrees()
+	if err != nil {
+		return err
+	}
+	for _, subtree := range subtrees {
+		entries, err := subtreeEntries(subtree)
+		if err != nil {
+			return err
+		}
+		modes := "create --name"
+		if subtree == "app" || subtree == "infra" {
+			modes += ", --" + subtree + "-only"
+		}
+		fmt.Printf("%s%s/ (%s):\n", indent, subtree, modes)
+		printTree(indent, entries, nil)
+	}
+	return nil
+}
+
+// subtreeEntries maps every path a subtree scaffolds, relative to the subtree,
+// to whether it is a directory. It includes the Python markers create adds.
+func subtreeEntries(subtree string) (map[string]bool, error) {
+	entries := map[string]bool{}
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		children, err := fs.ReadDir(templateFS, dir)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			srcPath, _, ok := templatePaths(templateFS, dir, dir, child)
+			if !ok {
+				continue
+			}
+			if !child.IsDir() {
+				name, _, _ := splitLocale(child.Name())
+				srcPath = path.Join(dir, name)
+			}
+			entries[strings.TrimPrefix(srcPath, subtree+"/")] = child.IsDir()
+			if child.IsDir() {
+				if err := walk(srcPath); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(subtree); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range pythonPackageDirs {
+		if rel, ok := strings.CutPrefix(dir, subtree+"/"); ok {
+			entries[rel] = true
+			entries[rel+"/__init__.py"] = false
+		}
+	}
+	return entries, nil
+}
+
+// printTree prints slash-separated paths as a tree indented below indent,
+// directories with a trailing slash and files with their size if sizes has one.
+func printTree(indent string, entries map[string]bool, sizes map[string]int64) {
+	paths := sortedKeys(entries)
+	slices.SortFunc(paths, func(a, b string) int {
+		return slices.Compare(strings.Split(a, "/"), strings.Split(b, "/"))
+	})
+	for _, p := range paths {
+		name := path.Base(p)
+		if entries[p] {
+			name += "/"
+		} else if size, ok := sizes[p]; ok {
+			name += " (" + formatBytes(size) + ")"
+		}
+		fmt.Printf("%s%s%s\n", indent, strings.Repeat("  ", strings.Count(p, "/")+1), name)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// templateMetaFile is the manifest at the templates root that names and
+// describes a template set.
+const templateMetaFile = "template.yaml"
+
+// templateMeta is the content of a template set's templateMetaFile.
+type templateMeta struct {
+	Name        string
+	Description string
+}
+
+// templateSet is a template tree list-templates can show.
+type templateSet struct {
+	// source is how to select the set: "embedded", or a --template value.
+	source string
+	fsys   fs.FS
+}
+
+// listTemplatesCmd represents the list-templates command
+var listTemplatesCmd = &cobra.Command{
+	Use:   "list-templates",
+	Short: "Show the available template sets and what each creates",
+	Long: `Print the embedded template set and every repository cached by create
+--template, each with the name and description from its template.yaml and the
+files and directories it would generate. Nothing is written to disk.
+Example: appinit list-templates`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		sets, err := templateSets()
+		if err != nil {
+			return err
+		}
+		previous := templateFS
+		defer func() { templateFS = previous }()
+		for i, set := range sets {
+			meta, err := readTemplateMeta(set.fsys)
+			if err != nil {
+				return fmt.Errorf("%s: %w", set.source, err)
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			if meta.Name != "" {
+				fmt.Printf("%s (%s)\n", meta.Name, set.source)
+			} else {
+				fmt.Println(set.source)
+			}
+			if meta.Description != "" {
+				fmt.Println("  " + meta.Description)
+			}
+			templateFS = set.fsys
+			if err := printTemplateLayout("  "); err != nil {
+				return fmt.Errorf("%s: %w", set.source, err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listTemplatesCmd)
+}
+
+// templateSets returns the embedded templates followed by the templates of
+// every cached --template repository.
+func templateSets() ([]templateSet, error) {
+	sets := []templateSet{{source: "embedded", fsys: embeddedTemplates()}}
+	cache, err := templateCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(cache)
+	if errors.Is(err, fs.ErrNotExist) {
+		return sets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(cache, entry.Name())
+		if info, err := os.Stat(filepath.Join(dir, "templates")); err != nil || !info.IsDir() {
+			continue
+		}
+		source := dir
+		if url, err := runGit(dir, "remote", "get-url", "origin"); err == nil {
+			source = url
+		}
+		sets = append(sets, templateSet{source: source, fsys: os.DirFS(filepath.Join(dir, "templates"))})
+	}
+	return sets, nil
+}
+
+// readTemplateMeta reads the metadata manifest of fsys; a set without one
+// has empty metadata.
+func readTemplateMeta(fsys fs.FS) (templateMeta, error) {
+	content, err := fs.ReadFile(fsys, templateMetaFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return templateMeta{}, nil
+	}
+	if err != nil {
+		return templateMeta{}, err
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return templateMeta{}, fmt.Errorf("invalid %s: %w", templateMetaFile, err)
+	}
+	return templateMeta{Name: v.GetString("name"), Description: v.GetString("description")}, nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// logFormats are the supported --log-format values.
+var logFormats = []string{"text", "json"}
+
+// Log flags override the DEBUG and ENV environment variables main reads.
+var (
+	verbose   bool
+	quiet     bool
+	logFormat string
+)
+
+// logger receives every log record of a run; nil means slog.Default(). The
+// package never replaces the default logger, so embedders keep control of it.
+var logger *slog.Logger
+
+// log returns the logger of the current run.
+func log() *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.BoolVarP(&verbose, "verbose", "v", false, "Log debug messages")
+	flags.BoolVarP(&quiet, "quiet", "q", false, "Log only warnings and errors")
+	flags.StringVar(&logFormat, "log-format", "", "Log format ("+strings.Join(logFormats, ", ")+"; default json when ENV=production, otherwise text)")
+	setEnum(flags, "log-format", logFormats)
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions(logFormats, cobra.ShellCompDirectiveNoFileComp)))
+}
+
+// configureLogging replaces the run's logger according to the log flags,
+// falling back to the DEBUG and ENV environment variables. Without any log
+// flags the logger passed to Execute is kept.
+func configureLogging() error {
+	if verbose && quiet {
+		return fmt.Errorf("--verbose and --quiet cannot be used together")
+	}
+	if logFormat != "" && !slices.Contains(logFormats, logFormat) {
+		return fmt.Errorf("unsupported --log-format %q (supported: %s)", logFormat, strings.Join(logFormats, ", "))
+	}
+	if !verbose && !quiet && logFormat == "" {
+		return nil
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case verbose:
+		level = slog.LevelDebug
+	case quiet:
+		level = slog.LevelWarn
+	case os.Getenv("DEBUG") != "":
+		level = slog.LevelDebug
+	}
+
+	production := os.Getenv("ENV") == "production"
+	format := logFormat
+	if format == "" {
+		format = "text"
+		if production {
+			format = "json"
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: level, AddSource: production}
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+	l := slog.New(handler)
+	if runID != "" {
+		l = l.With("run_id", runID)
+	}
+	logger = l
+	return nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// manifestOutput is the file the template manifest is written to; empty means stdout.
+var manifestOutput string
+
+// templateManifestCmd represents the template-manifest command
+var templateManifestCmd = &cobra.Command{
+	Use:   "template-manifest",
+	Short: "Write an inventory of the templates as JSON",
+	Long: `Write the path, size, SHA-256 hash, text/binary classification, and whether
+create renders it as a template, of every template file as JSON, without
+scaffolding anything. Without a template flag this lists everything embedded in
+the binary; --template-dir, --template-repo, or --template list the templates
+create would use with the same flag instead.
+Example: appinit template-manifest
+Example: appinit template-manifest --template-dir ./my-templates
+Example: appinit template-manifest --output templates.manifest.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		var fsys fs.FS = assets.Templates
+		if templateDir != "" || templateRepo != "" || templateSource != "" {
+			cleanup, err := useTemplateFlags()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			fsys = templateFS
+		}
+		entries, err := buildTemplateManifest(fsys)
+		if err != nil {
+			return err
+		}
+
+		if manifestOutput == "" {
+			return printJSON(entries)
+		}
+		data, err := marshalJSON(entries)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(manifestOutput, data, 0644); err != nil {
+			return err
+		}
+		log().Info("template manifest written", "path", manifestOutput, "files", len(entries))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateManifestCmd)
+	templateManifestCmd.Flags().StringVarP(&manifestOutput, "output", "o", "", "File to write the manifest to (default stdout)")
+	// Shares create's template flags, so the manifest lists what create would use
+	templateManifestCmd.Flags().StringVar(&templateDir, "template-dir", "", "List this template directory instead of the built-in templates")
+	templateManifestCmd.Flags().StringVar(&templateSource, "template", "", "List a local template directory or a git repository such as github.com/org/templates (cached in ~/.appinit/templates)")
+	templateManifestCmd.Flags().StringVar(&templateRepo, "template-repo", "", "List the templates/ directory of this git repository (shallow-cloned for the run)")
+	templateManifestCmd.Flags().StringVar(&templateRef, "template-ref", "", "Branch or tag of --template-repo to use")
+}
+
+// manifestEntry describes one template file.
+type manifestEntry struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+	Kind   string `json:"kind"`
+	// Rendered is set for files create runs through the template engine;
+	// the others are copied verbatim.
+	Rendered bool `json:"rendered"`
+}
+
+// buildTemplateManifest walks fsys and describes every file in it.
+func buildTemplateManifest(fsys fs.FS) ([]manifestEntry, error) {
+	entries := []manifestEntry{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(content)
+		kind := "text"
+		if isBinary(content) {
+			kind = "binary"
+		}
+		entries = append(entries, manifestEntry{
+			Path:     p,
+			Size:     len(content),
+			SHA256:   hex.EncodeToString(sum[:]),
+			Kind:     kind,
+			Rendered: isTemplated(content),
+		})
+		return nil
+	})
+	return entries, err
+}
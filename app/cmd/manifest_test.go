@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBuildTemplateManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":      {Data: []byte("# {{.AppName}}\n")},
+		"app/main.py":    {Data: []byte("print()\n")},
+		"app/logo.png":   {Data: []byte("\x89PNG\x00{{")},
+		"infra/cdk.json": {Data: []byte("{}")},
+	}
+	entries, err := buildTemplateManifest(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		kind     string
+		rendered bool
+	}{
+		"README.md":      {"text", true},
+		"app/main.py":    {"text", false},
+		"app/logo.png":   {"binary", false},
+		"infra/cdk.json": {"text", false},
+	}
+	if len(entries) != len(want) {
+		t.Errorf("got %d entries, want %d", len(entries), len(want))
+	}
+	for _, e := range entries {
+		w, ok := want[e.Path]
+		if !ok {
+			t.Errorf("unexpected entry %s", e.Path)
+			continue
+		}
+		if e.Kind != w.kind || e.Rendered != w.rendered {
+			t.Errorf("%s: kind %s, rendered %v; want %s, %v", e.Path, e.Kind, e.Rendered, w.kind, w.rendered)
+		}
+		if e.Size != len(fsys[e.Path].Data) || len(e.SHA256) != 64 {
+			t.Errorf("%s: size %d, sha256 %q", e.Path, e.Size, e.SHA256)
+		}
+	}
+}
+
+// readManifest runs template-manifest with args and parses its output.
+func readManifest(t *testing.T, args ...string) map[string]manifestEntry {
+	t.Helper()
+	mustExecute(t, append([]string{"template-manifest", "--output", "manifest.json"}, args...)...)
+	data, err := os.ReadFile("manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []manifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	byPath := map[string]manifestEntry{}
+	for _, e := range entries {
+		byPath[e.Path] = e
+	}
+	return byPath
+}
+
+func TestTemplateManifestSource(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":       "# {{.AppName}}",
+		"tpl/app/src/main.py": "print()",
+	})
+
+	embedded := readManifest(t)
+	if e, ok := embedded["templates/README.md"]; !ok || e.Kind != "text" {
+		t.Errorf("embedded manifest has no text templates/README.md: %v", embedded)
+	}
+
+	for _, flag := range []string{"--template-dir", "--template"} {
+		entries := readManifest(t, flag, "tpl")
+		if len(entries) != 2 {
+			t.Errorf("%s: got %d entries, want the 2 files of tpl: %v", flag, len(entries), entries)
+		}
+		if !entries["README.md"].Rendered || entries["app/src/main.py"].Rendered {
+			t.Errorf("%s: rendered flags wrong: %v", flag, entries)
+		}
+	}
+
+	if _, err := execute(t, "template-manifest", "--template-dir", "missing"); err == nil {
+		t.Error("--template-dir missing: want an error")
+	}
+}
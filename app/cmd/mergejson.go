@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// mergeArrayStrategies lists the supported --merge-json-arrays values. With
+// "keep" an existing array is kept as is, with "replace" the template array
+// takes its place, and "concat" appends template elements the existing array
+// lacks.
+var mergeArrayStrategies = []string{"keep", "replace", "concat"}
+
+// jsonObject is a decoded JSON object that remembers its key order.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// mergeJSONContent deep-merges template JSON into existing JSON. Keys missing
+// from existing are filled in from the template; existing values win.
+func mergeJSONContent(existing, template []byte, arrays string) ([]byte, error) {
+	base, err := decodeOrderedJSON(existing)
+	if err != nil {
+		return nil, fmt.Errorf("existing file: %w", err)
+	}
+	overlay, err := decodeOrderedJSON(template)
+	if err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeOrderedJSON(&buf, mergeJSONValues(base, overlay, arrays), 0); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// mergeJSONValues merges overlay into base and returns the result.
+func mergeJSONValues(base, overlay any, arrays string) any {
+	switch b := base.(type) {
+	case *jsonObject:
+		o, ok := overlay.(*jsonObject)
+		if !ok {
+			return base
+		}
+		for _, key := range o.keys {
+			if existing, ok := b.values[key]; ok {
+				b.values[key] = mergeJSONValues(existing, o.values[key], arrays)
+			} else {
+				b.keys = append(b.keys, key)
+				b.values[key] = o.values[key]
+			}
+		}
+		return b
+	case []any:
+		o, ok := overlay.([]any)
+		if !ok || arrays == "keep" {
+			return base
+		}
+		if arrays == "replace" {
+			return o
+		}
+		for _, element := range o {
+			if !containsJSON(b, element) {
+				b = append(b, element)
+			}
+		}
+		return b
+	default:
+		return base
+	}
+}
+
+// containsJSON reports whether values holds an element deeply equal to v.
+func containsJSON(values []any, v any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeOrderedJSON decodes a single JSON document, keeping object key order.
+func decodeOrderedJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeOrderedValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+// decodeOrderedValue decodes the next JSON value from dec.
+func decodeOrderedValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := &jsonObject{values: map[string]any{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token()
+		return arr, err
+	default:
+		return tok, nil
+	}
+}
+
+// encodeOrderedJSON writes v as indented JSON, keeping object key order.
+func encodeOrderedJSON(buf *bytes.Buffer, v any, depth int) error {
+	indent := strings.Repeat("  ", depth+1)
+	closing := strings.Repeat("  ", depth)
+
+	switch value := v.(type) {
+	case *jsonObject:
+		if len(value.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, key := range value.keys {
+			buf.WriteString(indent)
+			if err := encodeJSONScalar(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := encodeOrderedJSON(buf, value.values[key], depth+1); err != nil {
+				return err
+			}
+			if i < len(value.keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(closing + "}")
+	case []any:
+		if len(value) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, element := range value {
+			buf.WriteString(indent)
+			if err := encodeOrderedJSON(buf, element, depth+1); err != nil {
+				return err
+			}
+			if i < len(value)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(closing + "]")
+	default:
+		return encodeJSONScalar(buf, value)
+	}
+	return nil
+}
+
+// encodeJSONScalar writes a string, number, bool, or null without HTML escaping.
+func encodeJSONScalar(buf *bytes.Buffer, v any) error {
+	var scalar bytes.Buffer
+	enc := json.NewEncoder(&scalar)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+	return nil
+}
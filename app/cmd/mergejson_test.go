@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+func TestMergeJSONContent(t *testing.T) {
+	existing := `{"name": "mine", "tags": ["a", "b"], "extra": {"keep": true}}`
+	template := `{"name": "template", "tags": ["b", "c"], "extra": {"add": 1}, "new": [1]}`
+
+	tests := []struct {
+		arrays string
+		want   string
+	}{
+		{arrays: "keep", want: `{"name":"mine","tags":["a","b"],"extra":{"keep":true,"add":1},"new":[1]}`},
+		{arrays: "replace", want: `{"name":"mine","tags":["b","c"],"extra":{"keep":true,"add":1},"new":[1]}`},
+		{arrays: "concat", want: `{"name":"mine","tags":["a","b","c"],"extra":{"keep":true,"add":1},"new":[1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arrays, func(t *testing.T) {
+			got, err := mergeJSONContent([]byte(existing), []byte(template), tt.arrays)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := mergeJSONContent([]byte(tt.want), []byte("{}"), tt.arrays)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// dirNameStyles lists the supported --normalize-dir-names transforms.
+var dirNameStyles = []string{"lower", "kebab", "snake"}
+
+// normalizeDirName applies the --normalize-dir-names transform to a directory name.
+func normalizeDirName(name string) string {
+	switch normalizeDirNames {
+	case "lower":
+		return strings.ToLower(name)
+	case "kebab":
+		return strings.Join(nameWords(name), "-")
+	case "snake":
+		return strings.Join(nameWords(name), "_")
+	default:
+		return name
+	}
+}
+
+// nameWords splits a name into lowercase words at separators and camelCase boundaries.
+func nameWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '_' || r == ' ' || r == '.':
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// defaultNameMaxLength is the longest file name most filesystems accept.
+const defaultNameMaxLength = 255
+
+// reservedNames are device names Windows refuses as a file or directory name,
+// with or without an extension.
+var reservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// invalidNameChars are characters Windows, and often macOS tooling, refuse in file names.
+const invalidNameChars = `<>:"|?*`
+
+// normalizeAppName cleans --name the way a shell user would expect, so "my-app/"
+// and "./my-app" both name my-app. It rejects absolute paths and names that
+// climb out of the output directory; anything else is left to validateAppName.
+func normalizeAppName(name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return name, nil
+	}
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return "", fmt.Errorf("--name %q must be relative, not an absolute path; use --output-dir to choose the parent directory", name)
+	}
+	cleaned := filepath.Clean(name)
+	if cleaned == "." {
+		return "", fmt.Errorf("--name %q does not name a directory", name)
+	}
+	if slices.Contains(strings.FieldsFunc(filepath.ToSlash(cleaned), func(r rune) bool { return r == '/' || r == '\\' }), "..") {
+		return "", fmt.Errorf("--name %q must not contain .. segments; use --output-dir to choose the parent directory", name)
+	}
+	return cleaned, nil
+}
+
+// validateAppName checks that --name is a single directory name that is valid
+// on every platform, not just the current one.
+func validateAppName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("--name must not be empty")
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("--name %q must be a directory name, not a path", name)
+	case strings.HasPrefix(name, "."):
+		return fmt.Errorf("--name %q must not start with a dot", name)
+	case strings.ContainsFunc(name, unicode.IsSpace):
+		return fmt.Errorf("--name %q must not contain whitespace; try %q", name, strings.Join(nameWords(name), "-"))
+	case strings.ContainsAny(name, invalidNameChars) || strings.ContainsFunc(name, unicode.IsControl):
+		return fmt.Errorf("--name %q contains characters not allowed in file names (%s or control characters)", name, invalidNameChars)
+	case strings.HasSuffix(name, "."):
+		return fmt.Errorf("--name %q must not end with a dot", name)
+	case len(name) > nameMaxLength:
+		return fmt.Errorf("--name %q is %d characters, over the limit of %d (see --name-max-length)", name, len(name), nameMaxLength)
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	if slices.Contains(reservedNames, strings.ToUpper(stem)) {
+		return fmt.Errorf("--name %q is a reserved device name on Windows", name)
+	}
+	return nil
+}
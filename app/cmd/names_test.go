@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateAppName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "my-app"},
+		{name: "my_app.v2"},
+		{name: "MyApp"},
+		{name: strings.Repeat("a", defaultNameMaxLength)},
+		{name: "", wantErr: "must not be empty"},
+		{name: "   ", wantErr: "must not be empty"},
+		{name: "../../etc", wantErr: "not a path"},
+		{name: "a/b", wantErr: "not a path"},
+		{name: `a\b`, wantErr: "not a path"},
+		{name: ".hidden", wantErr: "must not start with a dot"},
+		{name: "..", wantErr: "must not start with a dot"},
+		{name: "my app", wantErr: `try "my-app"`},
+		{name: "tab\tname", wantErr: "whitespace"},
+		{name: "what?", wantErr: "not allowed in file names"},
+		{name: "a:b", wantErr: "not allowed in file names"},
+		{name: "bell\a", wantErr: "not allowed in file names"},
+		{name: "trailing.", wantErr: "must not end with a dot"},
+		{name: strings.Repeat("a", defaultNameMaxLength+1), wantErr: "over the limit of 255"},
+		{name: "CON", wantErr: "reserved device name"},
+		{name: "nul", wantErr: "reserved device name"},
+		{name: "com1.txt", wantErr: "reserved device name"},
+		{name: "LPT9", wantErr: "reserved device name"},
+	}
+	for _, tt := range tests {
+		err := validateAppName(tt.name)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("validateAppName(%q) = %v, want nil", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("validateAppName(%q) = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateAppNameMaxLength(t *testing.T) {
+	nameMaxLength = 8
+	t.Cleanup(func() { nameMaxLength = defaultNameMaxLength })
+	if err := validateAppName("eightchr"); err != nil {
+		t.Errorf("8 characters: %v", err)
+	}
+	if err := validateAppName("ninechars"); err == nil || !strings.Contains(err.Error(), "--name-max-length") {
+		t.Errorf("9 characters: got %v, want a --name-max-length error", err)
+	}
+}
+
+func TestNameWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"my-app", []string{"my", "app"}},
+		{"My App", []string{"my", "app"}},
+		{"myApp", []string{"my", "app"}},
+		{"HTTPServer", []string{"http", "server"}},
+		{"snake_case.name", []string{"snake", "case", "name"}},
+		{"--", nil},
+	}
+	for _, tt := range tests {
+		if got := nameWords(tt.name); !slices.Equal(got, tt.want) {
+			t.Errorf("nameWords(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeDirName(t *testing.T) {
+	t.Cleanup(func() { normalizeDirNames = "" })
+	tests := []struct {
+		style, name, want string
+	}{
+		{"", "MyDir", "MyDir"},
+		{"lower", "MyDir", "mydir"},
+		{"kebab", "MyDir_Name", "my-dir-name"},
+		{"snake", "my-dir Name", "my_dir_name"},
+	}
+	for _, tt := range tests {
+		normalizeDirNames = tt.style
+		if got := normalizeDirName(tt.name); got != tt.want {
+			t.Errorf("%q style: normalizeDirName(%q) = %q, want %q", tt.style, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAppName(t *testing.T) {
+	tests := []struct {
+		name, want, wantErr string
+	}{
+		{name: "", want: ""},
+		{name: "foo", want: "foo"},
+		{name: "foo/", want: "foo"},
+		{name: "./foo", want: "foo"},
+		{name: "foo//bar", want: "foo/bar"},
+		{name: "foo/./bar/", want: "foo/bar"},
+		{name: "/abs/foo", wantErr: "must be relative"},
+		{name: ".", wantErr: "does not name a directory"},
+		{name: "./", wantErr: "does not name a directory"},
+		{name: "../foo", wantErr: "must not contain .. segments"},
+		{name: "foo/../bar", want: "bar"},
+		{name: "foo/../../bar", wantErr: "must not contain .. segments"},
+	}
+	for _, tt := range tests {
+		got, err := normalizeAppName(tt.name)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("normalizeAppName(%q) = %q, %v; want an error containing %q", tt.name, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeAppName(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateNormalizesName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{name: "foo/", want: "foo"},
+		{name: "./foo", want: "foo"},
+		{name: "foo//bar", wantErr: "not a path"},
+		{name: "/abs/foo", wantErr: "must be relative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := chdirTemp(t)
+			res, err := execute(t, "create", "--name", tt.name, "--no-tree")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want an error containing %q", err, tt.wantErr)
+				}
+				assertMissing(t, dir, "foo", "abs")
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range res.Created {
+				if p != filepath.Clean(p) || !strings.HasPrefix(p, tt.want) {
+					t.Errorf("created %q, want clean paths under %s", p, tt.want)
+				}
+			}
+			assertExists(t, dir, tt.want+"/README.md")
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+)
+
+// compactJSON makes every JSON output minified instead of indented.
+var compactJSON bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&compactJSON, "compact-json", false, "Emit minified single-line JSON from all JSON outputs")
+}
+
+// marshalJSON encodes v with a trailing newline, indented unless --compact-json is set.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if !compactJSON {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// levelFilter drops records below min before they reach the wrapped handler.
+type levelFilter struct {
+	slog.Handler
+	min slog.Level
+}
+
+// Enabled reports whether both the filter and the wrapped handler accept level.
+func (f levelFilter) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= f.min && f.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs keeps the filter on loggers derived with attributes.
+func (f levelFilter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelFilter{f.Handler.WithAttrs(attrs), f.min}
+}
+
+// WithGroup keeps the filter on loggers derived with a group.
+func (f levelFilter) WithGroup(name string) slog.Handler {
+	return levelFilter{f.Handler.WithGroup(name), f.min}
+}
+
+// printJSON writes v to stdout as JSON.
+func printJSON(v any) error {
+	data, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// checkGroupModeSupport reports whether --group-writable and --setgid can be honored.
+func checkGroupModeSupport() error {
+	if groupWritable || setgid {
+		return errors.New("--group-writable and --setgid are only supported on Unix")
+	}
+	return nil
+}
+
+// applyGroupMode is a no-op on platforms without Unix permission bits.
+func applyGroupMode(path string, perm os.FileMode, isDir bool) error {
+	return nil
+}
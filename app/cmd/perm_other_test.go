@@ -0,0 +1,25 @@
+//go:build !unix
+
+package cmd
+
+import "testing"
+
+func TestCheckGroupModeSupport(t *testing.T) {
+	t.Cleanup(func() { groupWritable, setgid = false, false })
+	tests := []struct {
+		groupWritable, setgid, wantErr bool
+	}{
+		{false, false, false},
+		{true, false, true},
+		{false, true, true},
+	}
+	for _, tt := range tests {
+		groupWritable, setgid = tt.groupWritable, tt.setgid
+		if err := checkGroupModeSupport(); (err != nil) != tt.wantErr {
+			t.Errorf("groupWritable=%v setgid=%v: err = %v, want error %v", tt.groupWritable, tt.setgid, err, tt.wantErr)
+		}
+		if err := applyGroupMode("missing", 0644, true); err != nil {
+			t.Errorf("applyGroupMode: %v", err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package cmd
+
+import "os"
+
+// checkGroupModeSupport reports whether --group-writable and --setgid can be honored.
+func checkGroupModeSupport() error {
+	return nil
+}
+
+// applyGroupMode ORs the group-write bit, and setgid for directories, into
+// the mode of a path just created with perm. An explicit chmod is needed
+// because the umask masks the mode passed to Mkdir and WriteFile.
+func applyGroupMode(path string, perm os.FileMode, isDir bool) error {
+	if !groupWritable && !setgid {
+		return nil
+	}
+	mode := perm
+	if groupWritable {
+		mode |= 0020
+	}
+	if isDir && setgid {
+		mode |= os.ModeSetgid
+	}
+	return destFS.Chmod(path, mode)
+}
@@ -0,0 +1,75 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyGroupMode(t *testing.T) {
+	t.Cleanup(func() { groupWritable, setgid = false, false })
+	tests := []struct {
+		name                  string
+		groupWritable, setgid bool
+		isDir                 bool
+		perm, want            os.FileMode
+	}{
+		{name: "file unchanged", perm: 0600, want: 0600},
+		{name: "file group writable", groupWritable: true, perm: 0644, want: 0664},
+		{name: "setgid ignored on files", setgid: true, perm: 0644, want: 0644},
+		{name: "dir group writable", groupWritable: true, isDir: true, perm: 0755, want: 0775},
+		{name: "dir setgid", setgid: true, isDir: true, perm: 0755, want: 0755 | os.ModeSetgid},
+		{name: "dir both", groupWritable: true, setgid: true, isDir: true, perm: 0755, want: 0775 | os.ModeSetgid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groupWritable, setgid = tt.groupWritable, tt.setgid
+			p := filepath.Join(t.TempDir(), "entry")
+			var err error
+			if tt.isDir {
+				err = os.Mkdir(p, tt.perm)
+			} else {
+				err = os.WriteFile(p, nil, tt.perm)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(p, tt.perm); err != nil {
+				t.Fatal(err)
+			}
+			if err := applyGroupMode(p, tt.perm, tt.isDir); err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode() & (os.ModePerm | os.ModeSetgid); got != tt.want {
+				t.Errorf("mode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupModeFlags(t *testing.T) {
+	chdirTemp(t)
+	if err := checkGroupModeSupport(); err != nil {
+		t.Fatalf("checkGroupModeSupport: %v", err)
+	}
+	mustExecute(t, "create", "--name", "p", "--group-writable", "--setgid")
+	for p, want := range map[string]os.FileMode{
+		"p":           0775 | os.ModeSetgid,
+		"p/app":       0775 | os.ModeSetgid,
+		"p/README.md": 0664,
+	} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode() & (os.ModePerm | os.ModeSetgid); got != want {
+			t.Errorf("%s: mode = %v, want %v", p, got, want)
+		}
+	}
+}
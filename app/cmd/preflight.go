@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+// writeRoot returns the top-level directory the selected mode writes into.
+func writeRoot() string {
+	switch {
+	case appOnly:
+		return filepath.Join(outputRoot(), "app")
+	case infraOnly:
+		return filepath.Join(outputRoot(), "infra")
+	default:
+		return targetDir()
+	}
+}
+
+// checkWritable confirms the scaffold target, or the nearest existing directory
+// it will be created under, accepts new files before anything is written.
+func checkWritable() error {
+	dir := writeRoot()
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("target %s exists and is not a directory", dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	probe, err := os.CreateTemp(dir, ".appinit-write-check-*")
+	if err != nil {
+		return fmt.Errorf("target not writable: %s (running as %s): %w", dir, currentUser(), err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// currentUser returns the name of the user running appinit, for error messages.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown user"
+}
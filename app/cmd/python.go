@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// pythonIdentifier matches a name Python can import as a module.
+var pythonIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pythonKeywords lists reserved words that cannot name a module.
+var pythonKeywords = []string{
+	"False", "None", "True", "and", "as", "assert", "async", "await", "break",
+	"class", "continue", "def", "del", "elif", "else", "except", "finally",
+	"for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal",
+	"not", "or", "pass", "raise", "return", "try", "while", "with", "yield",
+}
+
+// checkPythonPackages verifies that every directory destined to be a Python
+// package, the marker packages and the directories under app/src, has a
+// valid identifier as its name.
+func checkPythonPackages() error {
+	dirs := append([]string{}, pythonPackageDirs...)
+	if _, err := fs.Stat(templateFS, "app/src"); err == nil {
+		err := fs.WalkDir(templateFS, "app/src", func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && p != "app/src" {
+				dirs = append(dirs, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var problems []string
+	for _, dir := range dirs {
+		name := path.Base(dir)
+		if err := checkPythonIdentifier(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", dir, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid Python package names: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkPythonIdentifier returns an error, with a suggested replacement, if name
+// cannot be imported as a Python module.
+func checkPythonIdentifier(name string) error {
+	if pythonIdentifier.MatchString(name) && !isPythonKeyword(name) {
+		return nil
+	}
+	return fmt.Errorf("%q is not a valid Python identifier; try %q", name, suggestPythonIdentifier(name))
+}
+
+// suggestPythonIdentifier turns name into a valid identifier, mainly by replacing hyphens with underscores.
+func suggestPythonIdentifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	suggestion := b.String()
+	if suggestion == "" || suggestion[0] >= '0' && suggestion[0] <= '9' || isPythonKeyword(suggestion) {
+		suggestion = "_" + suggestion
+	}
+	return suggestion
+}
+
+// isPythonKeyword reports whether name is a reserved word.
+func isPythonKeyword(name string) bool {
+	return slices.Contains(pythonKeywords, name)
+}
+
+// sourcePackages returns the Python packages of a template subtree, relative
+// to its source root: directories under <subtree>/src plus the subtree's
+// marker packages other than tests. Parents come before their children.
+func sourcePackages(subtree string) ([]string, error) {
+	var packages []string
+	srcRoot := subtree + "/src"
+	if _, err := fs.Stat(templateFS, srcRoot); err == nil {
+		err := fs.WalkDir(templateFS, srcRoot, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && p != srcRoot {
+				packages = append(packages, strings.TrimPrefix(p, srcRoot+"/"))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, dir := range pythonPackageDirs {
+		if pkg, ok := strings.CutPrefix(dir, subtree+"/"); ok && pkg != "tests" {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages, nil
+}
+
+// mirrorTestPackages creates a package under each subtree's tests/ for every
+// source package, so the test layout mirrors the source layout.
+func mirrorTestPackages(base string, subtrees ...string) error {
+	for _, subtree := range subtrees {
+		if !slices.Contains(pythonPackageDirs, subtree+"/tests") {
+			continue
+		}
+		packages, err := sourcePackages(subtree)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range packages {
+			dir := filepath.Join(base, subtree, "tests", filepath.FromSlash(replacePlaceholder(pkg)))
+			if err := createDirectory(dir); err != nil {
+				return err
+			}
+			if templateWrites(path.Join(subtree, "tests", pkg, "__init__.py")) {
+				continue
+			}
+			if marker := filepath.Join(dir, "__init__.py"); !structureSkips(marker) {
+				if err := createFile(marker, []byte{}, 0644); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
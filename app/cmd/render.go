@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateData is the context template files are rendered with.
+type templateData struct {
+	AppName     string
+	PackageName string
+	ModuleName  string
+	Year        int
+	Locale      string
+	Framework   string
+	Stack       string
+	Projects    []string
+	HasApp      bool
+	Deps        []string
+	Author      string
+	// Renamed maps the template path of each directory written under
+	// another name, such as app/MyLib, to its path in the project.
+	Renamed map[string]string
+}
+
+// newTemplateData returns the rendering context for this run.
+func newTemplateData() templateData {
+	name := projectName()
+	projects := scaffoldedProjects()
+	stack, _ := projectStack()
+	return templateData{
+		AppName:     name,
+		PackageName: strings.Join(nameWords(name), "-"),
+		ModuleName:  moduleName(name),
+		Year:        time.Now().Year(),
+		Locale:      locale,
+		Framework:   appFramework,
+		Stack:       stack,
+		Projects:    projects,
+		HasApp:      slices.Contains(projects, "app"),
+		Deps:        deps,
+		Author:      licenseAuthor,
+		Renamed:     templateRenames,
+	}
+}
+
+// projectName is the --name value, or the current directory's name for modes
+// that scaffold into it.
+func projectName() string {
+	if appName != "" {
+		return filepath.Base(appName)
+	}
+	if root, err := filepath.Abs(outputRoot()); err == nil {
+		return filepath.Base(root)
+	}
+	return ""
+}
+
+// moduleName turns a project name into a Python package name, such as
+// my_app for My-App.
+func moduleName(name string) string {
+	return suggestPythonIdentifier(strings.Join(nameWords(name), "_"))
+}
+
+// renderTemplate runs a template file through text/template. Files without
+// template actions and binary files are returned unchanged.
+func renderTemplate(srcPath string, content []byte) ([]byte, error) {
+	if !isTemplated(content) {
+		return content, nil
+	}
+
+	tmpl, err := template.New(srcPath).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", srcPath, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newTemplateData()); err != nil {
+		return nil, fmt.Errorf("render template %s: %w", srcPath, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// isTemplated reports whether renderTemplate runs content through the
+// template engine rather than copying it verbatim.
+func isTemplated(content []byte) bool {
+	return !isBinary(content) && bytes.Contains(content, []byte("{{"))
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	appName = "My-App"
+	t.Cleanup(func() { appName = "" })
+	year := strconv.Itoa(time.Now().Year())
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{name: "app name", content: "# {{.AppName}}\n", want: "# My-App\n"},
+		{name: "package and module", content: "{{.PackageName}} {{.ModuleName}}", want: "my-app my_app"},
+		{name: "year", content: "(c) {{.Year}}", want: "(c) " + year},
+		{name: "no actions", content: "plain text with } braces {", want: "plain text with } braces {"},
+		{name: "binary", content: "\x00{{.AppName}}\xff", want: "\x00{{.AppName}}\xff"},
+		{name: "invalid utf-8", content: "\xc3\x28{{.AppName}}", want: "\xc3\x28{{.AppName}}"},
+		{name: "missing key", content: "{{.Nope}}", wantErr: "render template src/file.txt"},
+		{name: "parse error", content: "{{.AppName", wantErr: "parse template src/file.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplate("src/file.txt", []byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderedRenames(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":                  `lib: {{index .Renamed "app/MyLib"}}, pkg: {{index .Renamed "app/MyLib/__project__"}}`,
+		"tpl/app/MyLib/__project__/x.py": "",
+		"tpl/app/src/main.py":            "",
+	})
+	mustExecute(t, "create", "--name", "My-App", "--template-dir", "tpl", "--normalize-dir-names", "snake", "--no-tree")
+	assertExists(t, "My-App", "app/my_lib/my_app/x.py")
+	got, err := os.ReadFile(filepath.Join("My-App", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "lib: app/my_lib, pkg: app/my_lib/my_app"; string(got) != want {
+		t.Errorf("README.md = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Result describes what a command did to the filesystem.
+type Result struct {
+	Created     []string `json:"created"`
+	Skipped     []string `json:"skipped"`
+	Overwritten []string `json:"overwritten"`
+	// Unchanged lists existing files that already matched the templates.
+	Unchanged []string `json:"unchanged"`
+	// Directories lists the entries of Created that are directories.
+	Directories []string `json:"directories,omitempty"`
+	// Renamed maps template directory paths to the normalized paths they were written to.
+	Renamed  map[string]string `json:"renamed,omitempty"`
+	Warnings []string          `json:"warnings"`
+	Errors   []string          `json:"errors"`
+	// Failed lists the paths that could not be written.
+	Failed []string `json:"failed,omitempty"`
+
+	// created indexes Created for createdThisRun.
+	created map[string]struct{}
+}
+
+// result accumulates the actions of the command currently executing.
+var result Result
+
+// stateMu guards result and the per-run counters while template files are
+// written concurrently.
+var stateMu sync.Mutex
+
+// recordPath appends path to one of the result's path lists.
+func recordPath(list *[]string, path string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	*list = append(*list, path)
+}
+
+// recordFailure adds path to the failed paths, once.
+func recordFailure(path string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if !slices.Contains(result.Failed, path) {
+		result.Failed = append(result.Failed, path)
+	}
+}
+
+// recordCreated adds path to the created paths, and to the directories if dir is set.
+func recordCreated(path string, dir bool) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if result.created == nil {
+		result.created = map[string]struct{}{}
+	}
+	result.created[path] = struct{}{}
+	result.Created = append(result.Created, path)
+	if dir {
+		result.Directories = append(result.Directories, path)
+	}
+}
+
+// createdThisRun reports whether path was created earlier in this run.
+func createdThisRun(path string) bool {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	_, ok := result.created[path]
+	return ok
+}
+
+// warn logs a warning and records it in the result.
+func warn(msg string, args ...any) {
+	log().Warn(msg, args...)
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+	}
+	recordPath(&result.Warnings, b.String())
+}
@@ -1,15 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "appinit",
-	Short: "Scaffold a new project with app and infra structure",
+	Use:           "appinit",
+	SilenceErrors: true,
+	Short:         "Scaffold a new project with app and infra structure",
 	Long: `appinit scaffolds a new project with a standardized directory structure
 for both application and infrastructure code.
 
@@ -23,15 +31,96 @@ This creates a project with the following structure:
   └── [templates] (pre-configured files)`,
 }
 
+// runID identifies this invocation in every log record so interleaved runs can be told apart.
+var runID string
+
+// newRunID returns a short random hex identifier.
+func newRunID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// ExecuteContext runs the command selected by args, such as
+// []string{"create", "--name", "my-app"}, and returns what it did without
+// calling os.Exit, leaving exit handling to the caller. Every flag starts from
+// its default, so values from an earlier call do not carry over. It uses
+// package state and is not safe for concurrent use.
+func ExecuteContext(ctx context.Context, args []string) (Result, error) {
+	res, _, err := executeArgs(ctx, args)
+	return res, err
+}
+
+// executeArgs is ExecuteContext that also reports whether a failure is to be
+// written as JSON. The decision reads flags, so it is made before they are reset.
+func executeArgs(ctx context.Context, args []string) (res Result, asJSON bool, err error) {
+	resetCommandFlags(rootCmd)
+	if args == nil {
+		// cobra falls back to os.Args for nil
+		args = []string{}
+	}
+	rootCmd.SetArgs(args)
+	defer resetCommandFlags(rootCmd)
+	result = Result{}
+	err = rootCmd.ExecuteContext(ctx)
+	if err != nil && !slices.Contains(result.Errors, err.Error()) {
+		// Create records its own error
+		result.Errors = append(result.Errors, err.Error())
+	}
+	return result, err != nil && errorsAsJSON(), err
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
+// Everything is logged through l, tagged with the run ID.
+func Execute(l *slog.Logger) {
+	runID = newRunID()
+	logger = l.With("run_id", runID)
+
+	if code := run(context.Background(), os.Args[1:], os.Stderr); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// run executes args and returns the process exit code. A failure is reported
+// on stderr as a JSON error object when requested, and logged otherwise.
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	_, asJSON, err := executeArgs(ctx, args)
+	if err == nil {
+		return 0
+	}
+	if asJSON {
+		writeErrorJSON(stderr, err)
+	} else {
+		log().Error("command failed", "error", err)
 	}
+	return errorExitCode
 }
 
 func init() {
 	// Global flags can be defined here if needed in the future
 }
+
+// resetFlags restores every flag in flags to its default and marks it unset.
+func resetFlags(flags *pflag.FlagSet) {
+	flags.VisitAll(func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+// resetCommandFlags restores the flags of cmd and all its subcommands to
+// their defaults.
+func resetCommandFlags(cmd *cobra.Command) {
+	resetFlags(cmd.PersistentFlags())
+	resetFlags(cmd.Flags())
+	for _, sub := range cmd.Commands() {
+		resetCommandFlags(sub)
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRunReportsErrorsAsJSON(t *testing.T) {
+	tests := [][]string{
+		{"create", "--name", "/abs/foo", "--quiet-errors-as-json"},
+		{"compare-projects", "missing-a", "missing-b", "--format", "json"},
+	}
+	for _, args := range tests {
+		chdirTemp(t)
+		var logs, stderr bytes.Buffer
+		logger = slog.New(slog.NewTextHandler(&logs, nil))
+		t.Cleanup(func() { logger = nil })
+
+		if code := run(context.Background(), args, &stderr); code != errorExitCode {
+			t.Errorf("%v: exit code %d, want %d", args, code, errorExitCode)
+		}
+		var report errorReport
+		if err := json.Unmarshal(stderr.Bytes(), &report); err != nil {
+			t.Errorf("%v: stderr is not a JSON error report: %v: %q", args, err, stderr.String())
+			continue
+		}
+		if report.Message == "" || report.ExitCode != errorExitCode {
+			t.Errorf("%v: report = %+v", args, report)
+		}
+		if bytes.Contains(logs.Bytes(), []byte("command failed")) {
+			t.Errorf("%v: failure was also logged as text: %s", args, logs.String())
+		}
+	}
+}
+
+func TestRunLogsErrorsByDefault(t *testing.T) {
+	chdirTemp(t)
+	var logs, stderr bytes.Buffer
+	logger = slog.New(slog.NewTextHandler(&logs, nil))
+	t.Cleanup(func() { logger = nil })
+
+	if code := run(context.Background(), []string{"create", "--name", "/abs/foo"}, &stderr); code != errorExitCode {
+		t.Errorf("exit code %d, want %d", code, errorExitCode)
+	}
+	if stderr.Len() != 0 || !bytes.Contains(logs.Bytes(), []byte("command failed")) {
+		t.Errorf("stderr %q, logs %q; want only the log line", stderr.String(), logs.String())
+	}
+}
+
+// TestExecuteErrorJSONProcess is the child process of TestExecuteErrorJSON.
+func TestExecuteErrorJSONProcess(t *testing.T) {
+	if os.Getenv("APPINIT_EXECUTE_ARGS") == "" {
+		t.Skip("run by TestExecuteErrorJSON")
+	}
+	os.Args = append([]string{"appinit"}, strings.Split(os.Getenv("APPINIT_EXECUTE_ARGS"), " ")...)
+	Execute(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+}
+
+func TestExecuteErrorJSON(t *testing.T) {
+	chdirTemp(t)
+	for _, args := range []string{
+		"create --name /abs/foo --quiet-errors-as-json",
+		"compare-projects missing-a missing-b --format json",
+	} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestExecuteErrorJSONProcess$")
+		cmd.Env = append(os.Environ(), "APPINIT_EXECUTE_ARGS="+args)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != errorExitCode {
+			t.Errorf("%s: err = %v, want exit code %d", args, err, errorExitCode)
+		}
+		if !json.Valid(bytes.TrimSpace(stderr.Bytes())) {
+			t.Errorf("%s: stderr is not JSON: %q", args, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// pytestNoTestsCollected is the pytest exit code for a run that found no tests.
+const pytestNoTestsCollected = 5
+
+// testDirs returns the scaffolded projects whose tests --run-tests runs.
+func testDirs() []string {
+	switch {
+	case appOnly:
+		return []string{filepath.Join(outputRoot(), "app")}
+	case infraOnly:
+		return []string{filepath.Join(outputRoot(), "infra")}
+	case rootOnly:
+		return nil
+	default:
+		subtrees, _ := selectedSubtrees()
+		var dirs []string
+		for _, subtree := range subtrees {
+			dirs = append(dirs, filepath.Join(targetDir(), subtree))
+		}
+		return dirs
+	}
+}
+
+// testCommand returns the command that runs the tests of the projects'
+// stack: pytest for python, preferring uv since the templates manage their
+// dev dependencies with it, and go test for go.
+func testCommand() (string, []string, error) {
+	stack, err := projectStack()
+	if err != nil {
+		return "", nil, fmt.Errorf("--run-tests: %w", err)
+	}
+	switch stack {
+	case "python":
+		if _, err := exec.LookPath("uv"); err == nil {
+			return "uv", []string{"run", "pytest"}, nil
+		}
+		if _, err := exec.LookPath("pytest"); err == nil {
+			return "pytest", nil, nil
+		}
+		return "", nil, errors.New("--run-tests requires uv or pytest on PATH")
+	case "go":
+		if _, err := exec.LookPath("go"); err != nil {
+			return "", nil, errors.New("--run-tests requires go on PATH")
+		}
+		return "go", []string{"test", "./..."}, nil
+	default:
+		return "", nil, fmt.Errorf("--run-tests does not support the %s stack", stack)
+	}
+}
+
+// runProjectTests runs the stack's tests in each scaffolded project and fails
+// if any tests fail.
+func runProjectTests() error {
+	name, args, err := testCommand()
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range testDirs() {
+		log().Info("running tests", "path", dir, "command", name)
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			log().Info("tests passed", "path", dir)
+		case name != "go" && errors.As(err, &exitErr) && exitErr.ExitCode() == pytestNoTestsCollected:
+			warn("no tests collected", "path", dir)
+		default:
+			return fmt.Errorf("tests failed in %s: %w", dir, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunTestsGo(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found on PATH")
+	}
+	// Keep the build cache of the user, which chdirTemp's HOME would hide
+	cache, err := exec.Command("go", "env", "GOCACHE").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"pass", "", false},
+		{"fail", `t.Fatal("broken")`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := chdirTemp(t)
+			t.Setenv("GOCACHE", strings.TrimSpace(string(cache)))
+			writeTree(t, dir, map[string]string{
+				"tpl/README.md":       "readme",
+				"tpl/app/go.mod":      "module example.com/app\n\ngo 1.21\n",
+				"tpl/app/app_test.go": "package app\n\nimport \"testing\"\n\nfunc TestApp(t *testing.T) {\n\t" + tt.body + "\n}\n",
+				"tpl/infra/go.mod":    "module example.com/infra\n\ngo 1.21\n",
+				"tpl/infra/infra.go":  "package infra\n",
+			})
+			_, err := execute(t, "create", "--name", "p", "--template-dir", "tpl", "--stack", "go", "--run-tests", "--no-tree")
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "tests failed in "+filepath.Join("p", "app")) {
+					t.Errorf("err = %v, want the app tests to fail", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertMissing(t, "p", "app/__init__.py", "app/tests/__init__.py")
+		})
+	}
+}
+
+func TestRunTestsPython(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake uv is a shell script")
+	}
+	dir := chdirTemp(t)
+	bin := filepath.Join(dir, "bin")
+	record := filepath.Join(dir, "uv-ran")
+	writeTree(t, bin, map[string]string{"uv": "#!/bin/sh\necho \"$(pwd) $*\" >> " + record + "\n"})
+	if err := os.Chmod(filepath.Join(bin, "uv"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	mustExecute(t, "create", "--name", "p", "--run-tests", "--no-tree")
+	got, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatalf("uv did not run: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(got)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("uv ran %d times, want once per project: %q", len(lines), lines)
+	}
+	for i, project := range []string{"app", "infra"} {
+		if !strings.HasSuffix(lines[i], filepath.Join("p", project)+" run pytest") {
+			t.Errorf("run %d: %q, want uv run pytest in p/%s", i, lines[i], project)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// enumAnnotation is the pflag annotation key listing a flag's valid values.
+const enumAnnotation = "appinit_enum"
+
+// hostDefaultAnnotation is the pflag annotation key describing a default that
+// depends on the machine, such as the CPU count, so the schema can describe it
+// instead of printing this machine's value.
+const hostDefaultAnnotation = "appinit_host_default"
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:    "schema",
+	Short:  "Print a JSON schema of the create options",
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return printJSON(flagSchema(createCmd))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// setEnum records the valid values of an enum-like flag.
+func setEnum(flags *pflag.FlagSet, name string, values []string) {
+	if err := flags.SetAnnotation(name, enumAnnotation, values); err != nil {
+		panic(err)
+	}
+}
+
+// setHostDefault records that the default of a flag is computed from the
+// machine, as described by desc.
+func setHostDefault(flags *pflag.FlagSet, name, desc string) {
+	if err := flags.SetAnnotation(name, hostDefaultAnnotation, []string{desc}); err != nil {
+		panic(err)
+	}
+}
+
+// flagSchema describes the local flags of cmd as a JSON schema object.
+func flagSchema(cmd *cobra.Command) map[string]any {
+	properties := map[string]any{}
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		property := map[string]any{"description": f.Usage}
+
+		switch f.Value.Type() {
+		case "bool":
+			property["type"] = "boolean"
+			property["default"], _ = strconv.ParseBool(f.DefValue)
+		case "int":
+			property["type"] = "integer"
+			property["default"], _ = strconv.Atoi(f.DefValue)
+		case "stringSlice", "stringArray":
+			property["type"] = "array"
+			property["items"] = map[string]any{"type": "string"}
+		default:
+			property["type"] = "string"
+			property["default"] = f.DefValue
+		}
+		if values, ok := f.Annotations[enumAnnotation]; ok {
+			property["enum"] = values
+			if !slices.Contains(values, f.DefValue) {
+				// An unset enum flag, like --license, has no default value
+				delete(property, "default")
+			}
+		}
+		if desc, ok := f.Annotations[hostDefaultAnnotation]; ok {
+			delete(property, "default")
+			property["description"] = f.Usage + " (default: " + desc[0] + ")"
+		}
+		properties[f.Name] = property
+	})
+
+	return map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      "appinit " + cmd.Name() + " options",
+		"type":       "object",
+		"properties": properties,
+	}
+}
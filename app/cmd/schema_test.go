@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFlagSchemaDefaults(t *testing.T) {
+	properties := flagSchema(createCmd)["properties"].(map[string]any)
+	for name, p := range properties {
+		property := p.(map[string]any)
+		enum, ok := property["enum"].([]string)
+		if !ok {
+			continue
+		}
+		if def, ok := property["default"]; ok && !slices.Contains(enum, def.(string)) {
+			t.Errorf("--%s: default %q is not one of %q", name, def, enum)
+		}
+	}
+
+	for _, name := range []string{"license", "normalize-dir-names"} {
+		if def, ok := properties[name].(map[string]any)["default"]; ok {
+			t.Errorf("--%s: unset enum has default %q", name, def)
+		}
+	}
+	if def := properties["stack"].(map[string]any)["default"]; def != "python" {
+		t.Errorf("--stack: default %v, want python", def)
+	}
+
+	workers := properties["workers"].(map[string]any)
+	if _, ok := workers["default"]; ok {
+		t.Errorf("--workers: default %v depends on the machine", workers["default"])
+	}
+	if desc := workers["description"].(string); !strings.Contains(desc, "number of CPUs") {
+		t.Errorf("--workers: description %q does not describe the default", desc)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// secretDetectors match common credential formats in rendered content.
+var secretDetectors = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret access key", regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}['"][0-9a-zA-Z/+]{40}['"]`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY( BLOCK)?-----`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+}
+
+// scanForSecrets checks content bound for path against the secret detectors.
+// Matches are warnings, or an error under --scan-secrets-fail.
+func scanForSecrets(path string, content []byte) error {
+	for _, detector := range secretDetectors {
+		if !detector.pattern.Match(content) {
+			continue
+		}
+		if scanSecretsFail {
+			return fmt.Errorf("possible %s found in %s; refusing to write it", detector.kind, path)
+		}
+		warn("possible secret in scaffolded file", "path", path, "type", detector.kind)
+	}
+	return nil
+}
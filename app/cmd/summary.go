@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// createSummary is the single JSON object written by --summary-json.
+type createSummary struct {
+	Target string `json:"target"`
+	// Created counts the files this run created; Directories counts the
+	// directories.
+	Created         int      `json:"created"`
+	Directories     int      `json:"directories"`
+	Skipped         int      `json:"skipped"`
+	SkippedExisting int      `json:"skipped_existing"`
+	Overwritten     int      `json:"overwritten"`
+	Unchanged       int      `json:"unchanged"`
+	Bytes           int64    `json:"bytes"`
+	DurationMS      int64    `json:"duration_ms"`
+	TemplateVersion string   `json:"template_version"`
+	RunID           string   `json:"run_id"`
+	Warnings        []string `json:"warnings"`
+	Failed          []string `json:"failed,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// writeSummaryJSON writes a summary of the finished run to dest, or stdout for "-".
+func writeSummaryJSON(dest string, runErr error) error {
+	summary := createSummary{
+		Target:          targetDir(),
+		Created:         len(result.Created) - len(result.Directories),
+		Directories:     len(result.Directories),
+		Skipped:         len(result.Skipped),
+		SkippedExisting: existingSkipped,
+		Overwritten:     len(result.Overwritten),
+		Unchanged:       len(result.Unchanged),
+		DurationMS:      time.Since(runStart).Milliseconds(),
+		TemplateVersion: templateVersion(),
+		RunID:           runID,
+		Warnings:        append([]string{}, result.Warnings...),
+		Failed:          append([]string(nil), result.Failed...),
+	}
+	for _, p := range generatedFiles() {
+		if info, err := destFS.Stat(p); err == nil {
+			summary.Bytes += info.Size()
+		}
+	}
+	if runErr != nil {
+		summary.Error = runErr.Error()
+	}
+
+	if dest == "-" {
+		return printJSON(summary)
+	}
+	data, err := marshalJSON(summary)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return err
+	}
+	log().Info("summary written", "path", dest)
+	return nil
+}
+
+// templateVersion identifies the template set by a hash of its manifest:
+// the whole embed, or the --template-dir or --template-repo tree.
+func templateVersion() string {
+	fsys, prefix := fs.FS(assets.Templates), "embedded-"
+	if templateDir != "" {
+		fsys, prefix = templateFS, "dir-"
+	} else if templateRepo != "" {
+		fsys, prefix = templateFS, "repo-"
+	}
+	entries, err := buildTemplateManifest(fsys)
+	if err != nil {
+		return "unknown"
+	}
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry.Path + " " + entry.SHA256 + "\n"))
+	}
+	return prefix + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// createReport is the JSON object printed by create --json.
+type createReport struct {
+	Name        string   `json:"name"`
+	Root        string   `json:"root"`
+	Directories []string `json:"directories"`
+	Files       []string `json:"files"`
+	Skipped     []string `json:"skipped"`
+	Overwritten []string `json:"overwritten"`
+	Unchanged   []string `json:"unchanged"`
+	RunID       string   `json:"run_id"`
+	Failed      []string `json:"failed,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// printCreateReport prints the paths this run created and skipped to stdout.
+func printCreateReport(runErr error) error {
+	report := createReport{
+		Name:        projectName(),
+		Root:        targetDir(),
+		Directories: append([]string{}, result.Directories...),
+		Files:       []string{},
+		Skipped:     append([]string{}, result.Skipped...),
+		Overwritten: append([]string{}, result.Overwritten...),
+		Unchanged:   append([]string{}, result.Unchanged...),
+		RunID:       runID,
+		Failed:      append([]string(nil), result.Failed...),
+	}
+	if root, err := filepath.Abs(report.Root); err == nil {
+		report.Root = root
+	}
+	for _, p := range result.Created {
+		if !slices.Contains(result.Directories, p) {
+			report.Files = append(report.Files, p)
+		}
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+	return printJSON(report)
+}
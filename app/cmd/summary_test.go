@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// readSummary parses a --summary-json file.
+func readSummary(t *testing.T, name string) createSummary {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary createSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	return summary
+}
+
+func TestSummaryCountsFilesAndDirectories(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"tpl/README.md":         "readme",
+		"tpl/app/src/main.py":   "print()",
+		"tpl/app/src/config.py": "",
+	})
+	mustExecute(t, "create", "--name", "p", "--template-dir", "tpl", "--summary-json", "summary.json", "--no-tree")
+	summary := readSummary(t, "summary.json")
+	// p, app, app/src, and app/tests; README.md, the marker, the two
+	// sources, and app/tests/__init__.py
+	if summary.Directories != 4 || summary.Created != 5 {
+		t.Errorf("created %d files and %d directories, want 5 and 4", summary.Created, summary.Directories)
+	}
+
+	mustExecute(t, "create", "--name", "p", "--template-dir", "tpl", "--summary-json", "summary.json", "--no-tree")
+	summary = readSummary(t, "summary.json")
+	if summary.Created != 0 || summary.Directories != 0 || summary.Unchanged != 5 {
+		t.Errorf("rerun: created %d, directories %d, unchanged %d; want 0, 0, 5", summary.Created, summary.Directories, summary.Unchanged)
+	}
+}
+
+func TestCreateJSONReport(t *testing.T) {
+	chdirTemp(t)
+	runID = "testrun1"
+	t.Cleanup(func() { runID = "" })
+	out := captureStdout(t, func() { mustExecute(t, "create", "--name", "p", "--json") })
+
+	var report createReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("create --json printed %q: %v", out, err)
+	}
+	if report.RunID != "testrun1" {
+		t.Errorf("run_id = %q, want testrun1", report.RunID)
+	}
+	if report.Name != "p" || !slices.Contains(report.Directories, "p") || !slices.Contains(report.Files, filepath.Join("p", "README.md")) {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// templateRepo is a git URL whose templates/ directory replaces the built-in templates.
+var templateRepo string
+
+// templateRef is the branch or tag of templateRepo to clone.
+var templateRef string
+
+// templateSource is the --template value: a local template directory or a
+// git repository, which is cached between runs.
+var templateSource string
+
+// templateCached keeps the clone of templateRepo under ~/.appinit/templates
+// and updates it on later runs instead of cloning for every run.
+var templateCached bool
+
+// templateClone is the clone of templateRepo used by the current run.
+var templateClone string
+
+// cacheKeyChars matches the characters replaced in a template cache directory name.
+var cacheKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// resolveTemplateSource maps --template onto --template-dir for a local
+// directory, or onto a cached --template-repo otherwise. A scheme-less
+// repository like github.com/org/templates is fetched over https.
+func resolveTemplateSource() error {
+	if templateSource == "" {
+		return nil
+	}
+	if templateDir != "" || templateRepo != "" {
+		return fmt.Errorf("--template cannot be combined with --template-dir or --template-repo")
+	}
+	if info, err := os.Stat(templateSource); err == nil && info.IsDir() {
+		if templateRef != "" {
+			return fmt.Errorf("--template-ref only applies when --template is a git repository, not the local directory %s", templateSource)
+		}
+		// A checkout of a template repository keeps the templates one level down
+		templateDir = templateSource
+		if info, err := os.Stat(filepath.Join(templateSource, "templates")); err == nil && info.IsDir() {
+			templateDir = filepath.Join(templateSource, "templates")
+		}
+		return nil
+	}
+	templateRepo = templateSource
+	if !strings.Contains(templateRepo, "://") && !strings.HasPrefix(templateRepo, "git@") {
+		templateRepo = "https://" + templateRepo
+	}
+	templateCached = true
+	return nil
+}
+
+// useTemplateFlags resolves --template, clones --template-repo, and points
+// templateFS at the templates they select. The returned cleanup removes a
+// temporary clone and must be called once the templates are no longer read.
+func useTemplateFlags() (func(), error) {
+	if err := resolveTemplateSource(); err != nil {
+		return nil, err
+	}
+	cleanup := func() {}
+	if templateRepo != "" {
+		clone, remove, err := cloneTemplateRepo()
+		if err != nil {
+			return nil, err
+		}
+		templateClone = clone
+		cleanup = func() {
+			remove()
+			templateClone = ""
+			templateCached = false
+		}
+	}
+	if err := loadTemplateDir(); err != nil {
+		cleanup()
+		return nil, err
+	}
+	return cleanup, nil
+}
+
+// cloneTemplateRepo shallow-clones templateRepo and checks that it has a
+// templates/ directory. The clone is temporary unless templateCached is set;
+// the returned cleanup removes a temporary clone and keeps a cached one.
+func cloneTemplateRepo() (string, func(), error) {
+	if templateDir != "" {
+		return "", nil, fmt.Errorf("--template-repo cannot be combined with --template-dir")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", nil, fmt.Errorf("--template-repo requires git: %w", err)
+	}
+	if templateCached {
+		dir, err := cachedTemplateRepo()
+		return dir, func() {}, err
+	}
+	dir, err := os.MkdirTemp("", "appinit-templates-")
+	if err != nil {
+		return "", nil, err
+	}
+	if err := shallowClone(dir); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	log().Debug("cloned template repository", "repo", templateRepo, "ref", templateRef, "path", dir)
+	return dir, func() { os.RemoveAll(dir) }, nil
+}
+
+// cachedTemplateRepo returns the cached clone of templateRepo, cloning it on
+// first use and updating it to the latest commit of templateRef afterwards.
+// A failed update, e.g. when offline, falls back to the cached commit.
+func cachedTemplateRepo() (string, error) {
+	cache, err := templateCacheDir()
+	if err != nil {
+		return "", err
+	}
+	key := templateRepo
+	if _, rest, ok := strings.Cut(key, "://"); ok {
+		key = rest
+	}
+	if templateRef != "" {
+		key += "@" + templateRef
+	}
+	dir := filepath.Join(cache, strings.Trim(cacheKeyChars.ReplaceAllString(key, "_"), "_."))
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		ref := templateRef
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if _, err := runGit(dir, "fetch", "--depth", "1", "--quiet", "origin", ref); err != nil {
+			warn("could not update cached templates, using the cached copy", "repo", templateRepo, "error", err)
+		} else if _, err := runGit(dir, "reset", "--hard", "--quiet", "FETCH_HEAD"); err != nil {
+			return "", fmt.Errorf("update cached --template %s: %w", templateRepo, err)
+		}
+		log().Debug("using cached template repository", "repo", templateRepo, "ref", templateRef, "path", dir)
+		return dir, checkTemplatesDir(dir)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create the template cache: %w", err)
+	}
+	if err := shallowClone(dir); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	log().Debug("cached template repository", "repo", templateRepo, "ref", templateRef, "path", dir)
+	return dir, nil
+}
+
+// templateCacheDir returns the directory --template keeps its clones in.
+func templateCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate the template cache: %w", err)
+	}
+	return filepath.Join(home, ".appinit", "templates"), nil
+}
+
+// shallowClone clones templateRepo at templateRef into the empty directory
+// dir and checks that it has a templates/ directory.
+func shallowClone(dir string) error {
+	args := []string{"clone", "--depth", "1", "--quiet"}
+	if templateRef != "" {
+		args = append(args, "--branch", templateRef)
+	}
+	args = append(args, "--", templateRepo, ".")
+	if _, err := runGit(dir, args...); err != nil {
+		return fmt.Errorf("clone --template-repo %s: %w", templateRepo, err)
+	}
+	return checkTemplatesDir(dir)
+}
+
+// checkTemplatesDir fails unless the clone in dir has a templates/ directory.
+func checkTemplatesDir(dir string) error {
+	if info, err := os.Stat(filepath.Join(dir, "templates")); err != nil || !info.IsDir() {
+		return fmt.Errorf("--template-repo %s has no templates/ directory at its root", templateRepo)
+	}
+	return nil
+}
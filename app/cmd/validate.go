@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// validateCreateFlags checks every create flag value and combination up front
+// and reports all problems together, so none is discovered mid-run or silently ignored.
+func validateCreateFlags(flags *pflag.FlagSet) error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// Scaffold mode
+	if countTrue(appOnly, infraOnly, rootOnly) > 1 {
+		add("only one of --app-only, --infra-only, or --root-only may be used")
+	}
+	if appName == "" && !appOnly && !infraOnly && !rootOnly {
+		add("either --name, --app-only, --infra-only, or --root-only is required")
+	}
+	if appName != "" && (appOnly || infraOnly) {
+		add("--name has no effect with --app-only or --infra-only, which write into the current directory; drop --name or use --root-only")
+	}
+
+	if appName != "" {
+		if _, err := normalizeAppName(appName); err != nil {
+			add("%v", err)
+		} else if err := validateAppName(appName); err != nil {
+			add("%v", err)
+		}
+	}
+	if nameMaxLength < 1 {
+		add("--name-max-length must be positive, got %d", nameMaxLength)
+	}
+	if workers < 1 {
+		add("--workers must be at least 1, got %d", workers)
+	}
+
+	if len(onlySubtrees) > 0 && (appOnly || infraOnly || rootOnly) {
+		add("--only cannot be combined with --app-only, --infra-only, or --root-only")
+	}
+	if _, err := selectedSubtrees(); err != nil {
+		add("%v", err)
+	}
+	if err := checkWithGroups(); err != nil {
+		add("%v", err)
+	}
+	if err := checkLicense(); err != nil {
+		add("%v", err)
+	}
+	if license != "" && (appOnly || infraOnly) {
+		add("--license has no effect with --app-only or --infra-only, which do not write root files")
+	}
+	if licenseAuthor != "" && license == "" {
+		add("--author requires --license")
+	}
+
+	// Enum values
+	if !slices.Contains(appFrameworks, appFramework) {
+		add("unsupported --app-framework %q (supported: %s)", appFramework, strings.Join(appFrameworks, ", "))
+	}
+	if !slices.Contains(locales, locale) {
+		add("unsupported --locale %q (supported: %s)", locale, strings.Join(locales, ", "))
+	}
+	if !slices.Contains(mergeArrayStrategies, mergeJSONArrays) {
+		add("unsupported --merge-json-arrays %q (supported: %s)", mergeJSONArrays, strings.Join(mergeArrayStrategies, ", "))
+	}
+	if !slices.Contains(stacks, stack) {
+		add("unsupported --stack %q (supported: %s)", stack, strings.Join(stacks, ", "))
+	}
+	if stack != "python" && templateDir == "" && templateRepo == "" {
+		add("the built-in templates are python; use --template-dir or --template-repo with --stack %s", stack)
+	}
+	if templateRef != "" && templateRepo == "" {
+		add("--template-ref requires --template-repo")
+	}
+	if stack != "python" {
+		for _, name := range []string{"mirror-tests", "dep", "validate-python-identifiers"} {
+			if flags.Changed(name) {
+				add("--%s only applies to --stack python", name)
+			}
+		}
+	}
+	if normalizeDirNames != "" && !slices.Contains(dirNameStyles, normalizeDirNames) {
+		add("unsupported --normalize-dir-names %q (supported: %s)", normalizeDirNames, strings.Join(dirNameStyles, ", "))
+	}
+	if logSample < 0 {
+		add("--log-sample must not be negative, got %d", logSample)
+	}
+
+	// Flags that would be ignored in the selected mode
+	if appFramework != "none" && len(onlySubtrees) > 0 && !slices.Contains(onlySubtrees, "app") {
+		add("--app-framework has no effect when --only excludes app")
+	}
+	if appFramework != "none" && (infraOnly || rootOnly) {
+		add("--app-framework has no effect with --infra-only or --root-only, which do not write the app directory")
+	}
+	if overwriteReadme && (appOnly || infraOnly) {
+		add("--overwrite-readme has no effect with --app-only or --infra-only, which do not write README.md")
+	}
+	if skipExistingDir && rootOnly {
+		add("--skip-existing-dir has no effect with --root-only, which writes no directories")
+	}
+	if force && skipExisting {
+		add("--force and --skip-existing cannot be used together")
+	}
+	if stripGitkeep && gitkeep {
+		add("--strip-gitkeep cannot be combined with --gitkeep, which adds the placeholders back")
+	}
+	if structureOnly {
+		if rootOnly {
+			add("--structure-only cannot be combined with --root-only, which writes only files")
+		}
+		for _, name := range []string{"license", "makefile", "pre-commit", "override", "dep", "merge-json", "overwrite-readme", "scan-secrets", "scan-secrets-fail"} {
+			if flags.Changed(name) {
+				add("--%s has no effect with --structure-only, which writes no template files", name)
+			}
+		}
+	}
+	if structureMarkers && !structureOnly {
+		add("--structure-markers requires --structure-only")
+	}
+	if archivePath != "" {
+		if !strings.HasSuffix(archivePath, ".tar.gz") && !strings.HasSuffix(archivePath, ".tgz") {
+			add("--archive must name a .tar.gz or .tgz file, got %q", archivePath)
+		}
+		for _, name := range []string{"dry-run", "git", "git-branch", "run-tests"} {
+			if flags.Changed(name) {
+				add("--archive cannot be combined with --%s, which needs the files on disk", name)
+			}
+		}
+	}
+	if len(deps) > 0 && !slices.Contains(scaffoldedProjects(), "app") {
+		add("--dep has no effect unless the app project is scaffolded")
+	}
+	for _, dep := range deps {
+		if strings.TrimSpace(dep) == "" || strings.ContainsAny(dep, "\"\\\n") {
+			add("invalid --dep %q: expected a dependency specifier like httpx>=0.28", dep)
+		}
+	}
+	if _, err := fs.Stat(assets.Templates, "generators/"+stack); err != nil {
+		for _, name := range []string{"makefile", "pre-commit"} {
+			if flags.Changed(name) {
+				add("--%s has no generator for --stack %s", name, stack)
+			}
+		}
+	}
+	if makefile && rootOnly {
+		add("--makefile has no effect with --root-only, which creates no projects to build")
+	}
+	if preCommit && rootOnly {
+		add("--pre-commit has no effect with --root-only, which creates no projects to check")
+	}
+	if mirrorTests && rootOnly {
+		add("--mirror-tests has no effect with --root-only, which creates no tests directories")
+	}
+	if runTests && rootOnly {
+		add("--run-tests has no effect with --root-only, which creates no projects to test")
+	}
+	if gitInit && (appOnly || infraOnly || appName == "") {
+		add("--git needs a new project directory; use --name without --app-only or --infra-only")
+	}
+	if gitInit && gitBranch != "" {
+		add("--git cannot be combined with --git-branch, which scaffolds into an existing repository")
+	}
+	if jsonOutput && summaryJSON == "-" {
+		add("--json and --summary-json - both write to stdout; write the summary to a file instead")
+	}
+	if jsonOutput && stats {
+		add("--stats prints to stdout and would corrupt the --json report")
+	}
+	if gitCommit && gitBranch == "" {
+		add("--git-commit requires --git-branch")
+	}
+	if flags.Changed("merge-json-arrays") && !mergeJSON {
+		add("--merge-json-arrays requires --merge-json")
+	}
+	if timeBudget > 0 && !verifyAfterWrite && !validateOutput && checksumsFile == "" && !runTests && !gitInit {
+		add("--time-budget only limits optional steps, and none are enabled; add one of --verify-after-write, --validate-output, --checksums, --run-tests, or --git, or drop --time-budget")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(configFlags) > 0 {
+		return fmt.Errorf("invalid flags: %s (%s set --%s; command-line flags override config values)",
+			strings.Join(problems, "; "), configPath, strings.Join(configFlags, ", --"))
+	}
+	return fmt.Errorf("invalid flags: %s", strings.Join(problems, "; "))
+}
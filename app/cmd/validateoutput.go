@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// validateOutput checks every generated file of a recognized type after the scaffold is written.
+var validateOutput bool
+
+// Python snippets run by --validate-output. Each reads the file content from
+// stdin, so files in an --archive are checked too, and takes the file path as
+// argv[1] for its messages; compile() is used instead of py_compile so no
+// __pycache__ is left behind.
+const (
+	pythonCompileCheck = `import sys; compile(sys.stdin.buffer.read(), sys.argv[1], "exec")`
+	pythonTOMLCheck    = `import sys, tomllib; tomllib.load(sys.stdin.buffer)`
+	pythonYAMLCheck    = `import sys
+try:
+    import yaml
+except ImportError:
+    sys.exit(3)
+list(yaml.safe_load_all(sys.stdin.buffer))`
+)
+
+// pythonCheckUnavailable is the exit code of a check whose Python module is not installed.
+const pythonCheckUnavailable = 3
+
+// pythonInterpreter returns the Python used for the --validate-output checks.
+func pythonInterpreter() (string, error) {
+	for _, name := range []string{"python3", "python"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", errors.New("--validate-output requires python3 or python on PATH")
+}
+
+// validateGeneratedFiles checks each generated file and, on the first invalid
+// one, removes what this run created and returns an error naming the file.
+func validateGeneratedFiles() error {
+	python, err := pythonInterpreter()
+	if err != nil {
+		return err
+	}
+
+	checked := 0
+	for _, p := range generatedFiles() {
+		ok, err := validateFile(python, p)
+		if err != nil {
+			rollbackCreated()
+			return fmt.Errorf("generated file %s is invalid: %w", p, err)
+		}
+		if ok {
+			checked++
+		}
+	}
+	log().Info("generated files validated", "count", checked)
+	return nil
+}
+
+// validateFile runs the syntax check for p's file type. It reports false for
+// files of an unrecognized type or whose checker is unavailable.
+func validateFile(python, p string) (bool, error) {
+	ext := filepath.Ext(p)
+	if !slices.Contains([]string{".json", ".go", ".py", ".toml", ".yml", ".yaml"}, ext) {
+		return false, nil
+	}
+	content, err := destFS.ReadFile(p)
+	if err != nil {
+		return false, err
+	}
+	switch ext {
+	case ".json":
+		var v any
+		return true, json.Unmarshal(content, &v)
+	case ".go":
+		_, err := parser.ParseFile(token.NewFileSet(), p, content, parser.AllErrors)
+		return true, err
+	case ".py":
+		return runPythonCheck(python, pythonCompileCheck, p, content)
+	case ".toml":
+		return runPythonCheck(python, pythonTOMLCheck, p, content)
+	default:
+		return runPythonCheck(python, pythonYAMLCheck, p, content)
+	}
+}
+
+// runPythonCheck runs a check snippet against p's content, returning the last
+// line of Python's output, which holds the exception, as the error.
+func runPythonCheck(python, script, p string, content []byte) (bool, error) {
+	cmd := exec.Command(python, "-c", script, p)
+	cmd.Stdin = bytes.NewReader(content)
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == pythonCheckUnavailable:
+		warn("no Python parser available for file, skipping validation", "path", p)
+		return false, nil
+	case errors.As(err, &exitErr):
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		return true, errors.New(lines[len(lines)-1])
+	default:
+		return false, err
+	}
+}
+
+// rollbackCreated removes the files and directories this run created, newest
+// first. Files it overwrote cannot be restored and are reported instead.
+func rollbackCreated() {
+	for _, p := range slices.Backward(result.Created) {
+		if err := destFS.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log().Error("failed to remove created path", "path", p, "error", err)
+		}
+	}
+	if len(result.Overwritten) > 0 {
+		warn("overwritten files were not restored", "count", len(result.Overwritten))
+	}
+	log().Info("created files removed", "count", len(result.Created))
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Build metadata, set at release time with
+// -ldflags "-X appinit/cmd.version=... -X appinit/cmd.commit=... -X appinit/cmd.date=...".
+// Values left empty are filled in from the binary's build info.
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// versionJSON prints the version fields as a JSON object.
+var versionJSON bool
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the appinit version, commit, and build date",
+	Long: `Print the version, git commit, and build date of this appinit binary.
+Example: appinit version
+Example: appinit version --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		info := buildVersion()
+		if versionJSON {
+			return printJSON(info)
+		}
+		fmt.Printf("appinit %s (commit %s, built %s)\n", info.Version, info.Commit, info.Date)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print the version as a JSON object")
+}
+
+// versionInfo describes this build.
+type versionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
+// buildVersion combines the -ldflags values with the module version and VCS
+// stamps recorded by the Go toolchain.
+func buildVersion() versionInfo {
+	info := versionInfo{Version: version, Commit: commit, Date: date}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			}
+		}
+	}
+
+	for _, field := range []*string{&info.Version, &info.Commit, &info.Date} {
+		if *field == "" {
+			*field = "unknown"
+		}
+	}
+	return info
+}
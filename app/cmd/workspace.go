@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"appinit/assets"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceFile is the name of the generated VS Code workspace file.
+const workspaceFile = "repo.code-workspace"
+
+// stackMarkers lists the files that identify the stacks of a project folder;
+// a folder can have several, like a Python CDK app.
+var stackMarkers = []struct{ file, stack string }{
+	{"pyproject.toml", "python"},
+	{"go.mod", "go"},
+	{"package.json", "node"},
+	{"cdk.json", "cdk"},
+}
+
+// stackExtensions lists the recommended VS Code extensions for each stack.
+var stackExtensions = map[string][]string{
+	"python": {"ms-python.python", "charliermarsh.ruff"},
+	"go":     {"golang.go"},
+	"node":   {"dbaeumer.vscode-eslint"},
+	"cdk":    {"amazonwebservices.aws-toolkit-vscode"},
+}
+
+// stackSettings lists the recommended workspace settings for each stack.
+// Object values shared between stacks, like files.exclude, are merged.
+var stackSettings = map[string]map[string]any{
+	"python": {
+		"python.testing.pytestEnabled": true,
+		"[python]": map[string]any{
+			"editor.defaultFormatter": "charliermarsh.ruff",
+			"editor.formatOnSave":     true,
+		},
+		"files.exclude": map[string]any{
+			"**/__pycache__":   true,
+			"**/.pytest_cache": true,
+			"**/.ruff_cache":   true,
+		},
+	},
+	"go": {
+		"[go]": map[string]any{"editor.formatOnSave": true},
+	},
+	"node": {
+		"editor.codeActionsOnSave": map[string]any{"source.fixAll.eslint": "explicit"},
+		"files.exclude":            map[string]any{"**/node_modules": true},
+	},
+	"cdk": {
+		"files.exclude":  map[string]any{"**/cdk.out": true},
+		"search.exclude": map[string]any{"**/cdk.out": true},
+	},
+}
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Generate a VS Code workspace file for the current directory",
+	Long: `Generate a repo.code-workspace for the current directory with a workspace
+folder for the root and for each subdirectory that looks like a project
+(contains pyproject.toml, go.mod, package.json, or cdk.json), plus
+recommended extensions and settings for the detected stacks. An existing
+workspace file that differs is handled like create handles existing files:
+the command fails unless --force or --skip-existing is given.
+Example: appinit workspace
+Example: appinit workspace --force         (replace an existing workspace file)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if force && skipExisting {
+			return errors.New("--force and --skip-existing cannot be used together")
+		}
+		resetRunState()
+
+		content, err := buildWorkspace(".")
+		if err != nil {
+			return err
+		}
+		if err := createFile(workspaceFile, content, 0644); err != nil {
+			return err
+		}
+		switch {
+		case len(result.Created) > 0 || len(result.Overwritten) > 0:
+			log().Info("workspace file written", "path", workspaceFile)
+		case len(result.Skipped) > 0:
+			log().Info("existing workspace file kept", "path", workspaceFile)
+		default:
+			log().Info("workspace file already up to date", "path", workspaceFile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	// Shares create's overwrite flags, so createFile applies the same policy
+	workspaceCmd.Flags().BoolVar(&force, "force", false, "Replace an existing workspace file that differs")
+	workspaceCmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "Keep an existing workspace file that differs")
+}
+
+// workspaceFolder is one entry of a workspace's folders list.
+type workspaceFolder struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// buildWorkspace renders a workspace file for the projects found under dir,
+// keeping the explanatory header comment from the embedded template.
+func buildWorkspace(dir string) ([]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []workspaceFolder{{Name: "root", Path: "."}}
+	var stacks []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		var folderStacks []string
+		for _, marker := range stackMarkers {
+			if _, err := os.Stat(filepath.Join(dir, entry.Name(), marker.file)); err != nil {
+				continue
+			}
+			folderStacks = append(folderStacks, marker.stack)
+			if !slices.Contains(stacks, marker.stack) {
+				stacks = append(stacks, marker.stack)
+			}
+		}
+		if len(folderStacks) > 0 {
+			folders = append(folders, workspaceFolder{Name: entry.Name(), Path: entry.Name()})
+			log().Debug("workspace folder detected", "path", entry.Name(), "stacks", folderStacks)
+		}
+	}
+	slices.Sort(stacks)
+
+	recommendations := []string{}
+	settings := map[string]any{}
+	for _, stack := range stacks {
+		recommendations = append(recommendations, stackExtensions[stack]...)
+		mergeSettings(settings, stackSettings[stack])
+	}
+
+	body, err := json.MarshalIndent(map[string]any{
+		"folders":    folders,
+		"settings":   settings,
+		"extensions": map[string]any{"recommendations": recommendations},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(workspaceHeader())
+	out.Write(body)
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// mergeSettings adds src to dst, merging the keys of object values both have
+// instead of replacing them. Nested objects are copied, so dst never shares
+// them with stackSettings.
+func mergeSettings(dst, src map[string]any) {
+	for key, value := range src {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		merged, ok := dst[key].(map[string]any)
+		if !ok {
+			merged = map[string]any{}
+			dst[key] = merged
+		}
+		mergeSettings(merged, obj)
+	}
+}
+
+// workspaceHeader returns the leading comment lines of the embedded workspace template.
+func workspaceHeader() []byte {
+	template, err := assets.Templates.ReadFile("templates/" + workspaceFile)
+	if err != nil {
+		return nil
+	}
+
+	var header bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(template))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		header.WriteString(line + "\n")
+	}
+	return header.Bytes()
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readWorkspace parses a workspace file, skipping its header comment.
+func readWorkspace(t *testing.T) map[string]any {
+	t.Helper()
+	content, err := os.ReadFile(workspaceFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := bytes.IndexByte(content, '{')
+	if start < 0 {
+		t.Fatalf("%s has no JSON object: %q", workspaceFile, content)
+	}
+	var ws map[string]any
+	if err := json.Unmarshal(content[start:], &ws); err != nil {
+		t.Fatal(err)
+	}
+	return ws
+}
+
+func TestWorkspaceSettings(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{
+		"app/pyproject.toml":   "",
+		"infra/pyproject.toml": "",
+		"infra/cdk.json":       "{}",
+		"web/package.json":     "{}",
+		"docs/index.md":        "",
+	})
+	mustExecute(t, "workspace")
+	ws := readWorkspace(t)
+
+	var folders []string
+	for _, f := range ws["folders"].([]any) {
+		folders = append(folders, f.(map[string]any)["path"].(string))
+	}
+	if want := []string{".", "app", "infra", "web"}; !slices.Equal(folders, want) {
+		t.Errorf("folders = %q, want %q", folders, want)
+	}
+
+	settings := ws["settings"].(map[string]any)
+	if settings["python.testing.pytestEnabled"] != true {
+		t.Errorf("python settings missing: %v", settings)
+	}
+	if _, ok := settings["editor.codeActionsOnSave"]; !ok {
+		t.Errorf("node settings missing: %v", settings)
+	}
+	excludes := settings["files.exclude"].(map[string]any)
+	for _, pattern := range []string{"**/__pycache__", "**/node_modules", "**/cdk.out"} {
+		if excludes[pattern] != true {
+			t.Errorf("files.exclude has no %s: %v", pattern, excludes)
+		}
+	}
+	if _, ok := settings["[go]"]; ok {
+		t.Error("go settings without a go folder")
+	}
+
+	var recommendations []string
+	for _, r := range ws["extensions"].(map[string]any)["recommendations"].([]any) {
+		recommendations = append(recommendations, r.(string))
+	}
+	for _, ext := range []string{"ms-python.python", "dbaeumer.vscode-eslint", "amazonwebservices.aws-toolkit-vscode"} {
+		if !slices.Contains(recommendations, ext) {
+			t.Errorf("recommendations = %q, want %s", recommendations, ext)
+		}
+	}
+}
+
+func TestWorkspaceOverwritePolicy(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTree(t, dir, map[string]string{"app/pyproject.toml": ""})
+	mustExecute(t, "workspace")
+	// An unchanged file is not a conflict
+	mustExecute(t, "workspace")
+
+	edited := "// edited\n{}\n"
+	writeTree(t, dir, map[string]string{workspaceFile: edited})
+	if _, err := execute(t, "workspace"); err == nil || !strings.Contains(err.Error(), "--skip-existing") {
+		t.Errorf("err = %v, want a conflict naming --force and --skip-existing", err)
+	}
+	if _, err := execute(t, "workspace", "--force", "--skip-existing"); err == nil {
+		t.Error("--force --skip-existing: want an error")
+	}
+
+	mustExecute(t, "workspace", "--skip-existing")
+	if got, _ := os.ReadFile(workspaceFile); string(got) != edited {
+		t.Errorf("--skip-existing replaced the file: %q", got)
+	}
+	mustExecute(t, "workspace", "--force")
+	if got, _ := os.ReadFile(workspaceFile); string(got) == edited {
+		t.Error("--force kept the edited file")
+	}
+}
@@ -27,6 +27,5 @@ func main() {
 		})
 	}
 
-	slog.SetDefault(slog.New(handler))
-	cmd.Execute()
+	cmd.Execute(slog.New(handler))
 }